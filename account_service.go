@@ -9,6 +9,10 @@ type ACTokenService struct {
 	c *Client
 }
 
+func (s *ACTokenService) Validate() error {
+	return nil
+}
+
 func (s *ACTokenService) Do(ctx context.Context, opts ...RequestOption) (res *GetACToken, err error) {
 	r := &request{
 		method:   http.MethodPost,
@@ -50,7 +54,23 @@ func (s *CCTokenService) ProviderCode(providerCode string) *CCTokenService {
 	return s
 }
 
+func (s *CCTokenService) Validate() error {
+	v := new(validator)
+	if len(s.scope) == 0 {
+		v.missing("scope")
+	}
+	if s.providerCode == "" {
+		v.missing("providerCode")
+	}
+	return v.err()
+}
+
 func (s *CCTokenService) Do(ctx context.Context, opts ...RequestOption) (res *GetCCToken, err error) {
+	err = s.Validate()
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
 		endpoint: "/oauth/v1/token",
@@ -86,6 +106,10 @@ type RefreshTokenService struct {
 	c *Client
 }
 
+func (s *RefreshTokenService) Validate() error {
+	return nil
+}
+
 func (s *RefreshTokenService) Do(ctx context.Context, opts ...RequestOption) (res *RefreshToken, err error) {
 	r := &request{
 		method:   http.MethodPost,
@@ -116,6 +140,10 @@ type RevokeTokenService struct {
 	c *Client
 }
 
+func (s *RevokeTokenService) Validate() error {
+	return nil
+}
+
 func (s *RevokeTokenService) Do(ctx context.Context, opts ...RequestOption) (res *RevokeToken, err error) {
 	r := &request{
 		method:   http.MethodPost,
@@ -139,12 +167,9 @@ func (s *RevokeTokenService) Do(ctx context.Context, opts ...RequestOption) (res
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type RevokeToken struct {
-	Error        bool   `json:"error"`
-	Message      string `json:"message"`
-	ResultNumber int    `json:"result_number"`
-}
+type RevokeToken = Response[string]
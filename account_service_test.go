@@ -0,0 +1,24 @@
+package sanbod
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRevokeTokenService(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		"/oauth/v1/revoke": `{"error":false,"message":"revoked","result_number":1}`,
+	})
+	c.cache.set(CacheAccessToken, "token")
+
+	res, err := c.NewRevokeTokenService().Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message != "revoked" {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	if f.calls["/oauth/v1/revoke"] != 1 {
+		t.Fatalf("unexpected calls %v", f.calls)
+	}
+}
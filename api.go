@@ -0,0 +1,194 @@
+package sanbod
+
+import (
+	"context"
+)
+
+// Doer is implemented by every service: Validate checks the inputs set on
+// it and Do sends the request. The setters are not part of it, so it suits
+// code that is handed a configured service.
+type Doer[T any] interface {
+	Validate() error
+	Do(ctx context.Context, opts ...RequestOption) (T, error)
+}
+
+type (
+	MatchNationalCodeWithMobileNumberDoer = Doer[*MatchNationalCodeWithMobileNumber]
+	MatchNationalCodeWithCardNumberDoer   = Doer[*MatchNationalCodeWithCardNumber]
+	InquiryUserProfileDoer                = Doer[*InquiryUserProfile]
+	InquiryUserProfileWithImageDoer       = Doer[*InquiryUserProfileWithImage]
+	CitizenshipVerificationDoer           = Doer[*CitizenshipVerification]
+	IbanInquiryDoer                       = Doer[*IbanInquiry]
+	CardToAccountNumberDoer               = Doer[*CardToAccountNumber]
+	CardToIbanDoer                        = Doer[*CardToIban]
+	AccountNumberToIbanDoer               = Doer[*AccountNumberToIban]
+	IbanToAccountNumberDoer               = Doer[*IbanToAccountNumber]
+	KYCDoer                               = Doer[*KYCReport]
+	BeneficiaryVerificationDoer           = Doer[*BeneficiaryVerification]
+	CCTokenDoer                           = Doer[*GetCCToken]
+	RefreshTokenDoer                      = Doer[*RefreshToken]
+	RevokeTokenDoer                       = Doer[*RevokeToken]
+)
+
+var (
+	_ MatchNationalCodeWithMobileNumberDoer = (*MatchNationalCodeWithMobileNumberService)(nil)
+	_ MatchNationalCodeWithCardNumberDoer   = (*MatchNationalCodeWithCardNumberService)(nil)
+	_ InquiryUserProfileDoer                = (*InquiryUserProfileService)(nil)
+	_ InquiryUserProfileWithImageDoer       = (*InquiryUserProfileWithImageService)(nil)
+	_ CitizenshipVerificationDoer           = (*CitizenshipVerificationService)(nil)
+	_ IbanInquiryDoer                       = (*IbanInquiryService)(nil)
+	_ CardToAccountNumberDoer               = (*CardToAccountNumberService)(nil)
+	_ CardToIbanDoer                        = (*CardToIbanService)(nil)
+	_ AccountNumberToIbanDoer               = (*AccountNumberToIbanService)(nil)
+	_ IbanToAccountNumberDoer               = (*IbanToAccountNumberService)(nil)
+	_ KYCDoer                               = (*KYCService)(nil)
+	_ BeneficiaryVerificationDoer           = (*BeneficiaryVerificationService)(nil)
+	_ CCTokenDoer                           = (*CCTokenService)(nil)
+	_ RefreshTokenDoer                      = (*RefreshTokenService)(nil)
+	_ RevokeTokenDoer                       = (*RevokeTokenService)(nil)
+
+	_ API = (*Client)(nil)
+)
+
+type ProfileRequest struct {
+	NationalCode NationalCode `json:"national_code"`
+	BirthDate    JalaliDate   `json:"birth_date"`
+}
+
+type MobileMatchRequest struct {
+	NationalCode NationalCode `json:"national_code"`
+	MobileNumber MobileNumber `json:"mobile_number"`
+}
+
+type CardMatchRequest struct {
+	NationalCode NationalCode `json:"national_code"`
+	CardNumber   CardNumber   `json:"card_number"`
+	MobileNumber MobileNumber `json:"mobile_number,omitempty"`
+}
+
+type DepositToIbanRequest struct {
+	Bank          Bank          `json:"bank"`
+	DepositNumber DepositNumber `json:"deposit_number"`
+}
+
+type KYCRequest struct {
+	NationalCode NationalCode `json:"national_code,omitempty"`
+	BirthDate    JalaliDate   `json:"birth_date"`
+	MobileNumber MobileNumber `json:"mobile_number,omitempty"`
+	CardNumber   CardNumber   `json:"card_number,omitempty"`
+	// Checks limits the run to the given checks when set.
+	Checks []KYCCheck `json:"checks,omitempty"`
+}
+
+type BeneficiaryRequest struct {
+	Iban         IBAN         `json:"iban"`
+	NationalCode NationalCode `json:"national_code"`
+	BirthDate    JalaliDate   `json:"birth_date"`
+	// Threshold overrides the default name match threshold when non-zero.
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// API is the request/response view of the client, for code that wants to
+// depend on an interface and swap in a fake such as sanbodmock.API in tests.
+type API interface {
+	InquireProfile(ctx context.Context, req ProfileRequest, opts ...RequestOption) (*InquiryUserProfile, error)
+	InquireProfileWithImage(ctx context.Context, req ProfileRequest, opts ...RequestOption) (*InquiryUserProfileWithImage, error)
+	VerifyCitizenship(ctx context.Context, req ProfileRequest, opts ...RequestOption) (*CitizenshipVerification, error)
+	MatchMobile(ctx context.Context, req MobileMatchRequest, opts ...RequestOption) (*MatchNationalCodeWithMobileNumber, error)
+	MatchCard(ctx context.Context, req CardMatchRequest, opts ...RequestOption) (*MatchNationalCodeWithCardNumber, error)
+	CardToDeposit(ctx context.Context, cardNumber CardNumber, opts ...RequestOption) (*CardToAccountNumber, error)
+	CardToIban(ctx context.Context, cardNumber CardNumber, opts ...RequestOption) (*CardToIban, error)
+	DepositToIban(ctx context.Context, req DepositToIbanRequest, opts ...RequestOption) (*AccountNumberToIban, error)
+	IbanToDeposit(ctx context.Context, iban IBAN, opts ...RequestOption) (*IbanToAccountNumber, error)
+	InquireIban(ctx context.Context, iban IBAN, opts ...RequestOption) (*IbanInquiry, error)
+	RunKYC(ctx context.Context, req KYCRequest, opts ...RequestOption) (*KYCReport, error)
+	VerifyBeneficiary(ctx context.Context, req BeneficiaryRequest, opts ...RequestOption) (*BeneficiaryVerification, error)
+}
+
+func (c *Client) InquireProfile(ctx context.Context, req ProfileRequest, opts ...RequestOption) (*InquiryUserProfile, error) {
+	return c.NewInquiryUserProfileService().
+		NationalCode(req.NationalCode).
+		Birthdate(req.BirthDate).
+		Do(ctx, opts...)
+}
+
+func (c *Client) InquireProfileWithImage(ctx context.Context, req ProfileRequest, opts ...RequestOption) (*InquiryUserProfileWithImage, error) {
+	return c.NewInquiryUserProfileWithImageService().
+		NationalCode(req.NationalCode).
+		Birthdate(req.BirthDate).
+		Do(ctx, opts...)
+}
+
+func (c *Client) VerifyCitizenship(ctx context.Context, req ProfileRequest, opts ...RequestOption) (*CitizenshipVerification, error) {
+	return c.NewCitizenshipVerificationService().
+		NationalCode(req.NationalCode).
+		Birthdate(req.BirthDate).
+		Do(ctx, opts...)
+}
+
+func (c *Client) MatchMobile(ctx context.Context, req MobileMatchRequest, opts ...RequestOption) (*MatchNationalCodeWithMobileNumber, error) {
+	return c.NewMatchNationalCodeWithMobileNumberService().
+		NationalCode(req.NationalCode).
+		MobileNumber(req.MobileNumber).
+		Do(ctx, opts...)
+}
+
+func (c *Client) MatchCard(ctx context.Context, req CardMatchRequest, opts ...RequestOption) (*MatchNationalCodeWithCardNumber, error) {
+	return c.NewMatchNationalCodeWithCardNumberService().
+		NationalCode(req.NationalCode).
+		CardNumber(req.CardNumber).
+		MobileNumber(req.MobileNumber).
+		Do(ctx, opts...)
+}
+
+func (c *Client) CardToDeposit(ctx context.Context, cardNumber CardNumber, opts ...RequestOption) (*CardToAccountNumber, error) {
+	return c.NewCardToAccountNumberService().
+		CardNumber(cardNumber).
+		Do(ctx, opts...)
+}
+
+func (c *Client) CardToIban(ctx context.Context, cardNumber CardNumber, opts ...RequestOption) (*CardToIban, error) {
+	return c.NewCardToIbanService().
+		CardNumber(cardNumber).
+		Do(ctx, opts...)
+}
+
+func (c *Client) DepositToIban(ctx context.Context, req DepositToIbanRequest, opts ...RequestOption) (*AccountNumberToIban, error) {
+	return c.NewAccountNumberToIbanService().
+		Provider(req.Bank).
+		DepositNumber(req.DepositNumber).
+		Do(ctx, opts...)
+}
+
+func (c *Client) IbanToDeposit(ctx context.Context, iban IBAN, opts ...RequestOption) (*IbanToAccountNumber, error) {
+	return c.NewIbanToAccountNumberService().
+		Iban(iban).
+		Do(ctx, opts...)
+}
+
+func (c *Client) InquireIban(ctx context.Context, iban IBAN, opts ...RequestOption) (*IbanInquiry, error) {
+	return c.NewIbanInquiryService().
+		Iban(iban).
+		Do(ctx, opts...)
+}
+
+func (c *Client) RunKYC(ctx context.Context, req KYCRequest, opts ...RequestOption) (*KYCReport, error) {
+	return c.NewKYCService().
+		NationalCode(req.NationalCode).
+		Birthdate(req.BirthDate).
+		MobileNumber(req.MobileNumber).
+		CardNumber(req.CardNumber).
+		Checks(req.Checks...).
+		Do(ctx, opts...)
+}
+
+func (c *Client) VerifyBeneficiary(ctx context.Context, req BeneficiaryRequest, opts ...RequestOption) (*BeneficiaryVerification, error) {
+	s := c.NewBeneficiaryVerificationService().
+		Iban(req.Iban).
+		NationalCode(req.NationalCode).
+		Birthdate(req.BirthDate)
+	if req.Threshold != 0 {
+		s.Threshold(req.Threshold)
+	}
+	return s.Do(ctx, opts...)
+}
@@ -0,0 +1,175 @@
+package sanbod
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Bank string
+
+const (
+	CentralBankOfTheIslamicRepublicOfIran Bank = "MARKAZI"
+	BankOfIndustryMine                    Bank = "SANAT_VA_MADAN"
+	BankMellat                            Bank = "MELLAT"
+	RefahKBank                            Bank = "REFAH"
+	BankMaskan                            Bank = "MASKAN"
+	BankSepah                             Bank = "SEPAH"
+	BankKeshavarziIran                    Bank = "KESHAVARZI"
+	BankMelliIran                         Bank = "MELLI"
+	TejaratBank                           Bank = "TEJARAT"
+	BankSaderatIran                       Bank = "SADERAT"
+	ExportDevelopmentBankOfIran           Bank = "TOSEAH_SADERAT"
+	PostBankIran                          Bank = "POST"
+	ToseeTaavonBank                       Bank = "TOSEAH_TAAVON"
+	KarafarinBank                         Bank = "KARAFARIN"
+	ParsianBank                           Bank = "PARSIAN"
+	EghtesadNovinBank                     Bank = "EGHTESAD_NOVIN"
+	SamanBank                             Bank = "SAMAN"
+	PasargadBank                          Bank = "PASARGAD"
+	SarmayehBank                          Bank = "SARMAYEH"
+	SinaBank                              Bank = "SINA"
+	GharzolhasaneMehrIranBank             Bank = "MEHR_IRAN"
+	ShahrBank                             Bank = "SHAHR"
+	AyandehBank                           Bank = "AYANDEH"
+	TourismBank                           Bank = "GARDESHGARI"
+	DayBank                               Bank = "DAY"
+	IranZaminBank                         Bank = "IRANZAMIN"
+	ResalatGharzolhasaneBank              Bank = "RESALAT"
+	MelalCreditInstitution                Bank = "MELAL"
+	MiddleEastBank                        Bank = "KHAVARMIANEH"
+	NoorCreditInstitution                 Bank = "NOOR"
+	IranVenezuelaBiNationalBank           Bank = "IRAN_VENEZUELA"
+	UNKNOWN                               Bank = "UNKNOWN"
+)
+
+type bankInfo struct {
+	persianName string
+	englishName string
+	ibanCode    string
+	bins        []string
+}
+
+var banks = map[Bank]bankInfo{
+	CentralBankOfTheIslamicRepublicOfIran: {"بانک مرکزی جمهوری اسلامی ایران", "Central Bank of the Islamic Republic of Iran", "010", []string{"636795"}},
+	BankOfIndustryMine:                    {"بانک صنعت و معدن", "Bank of Industry and Mine", "011", []string{"627961"}},
+	BankMellat:                            {"بانک ملت", "Bank Mellat", "012", []string{"610433", "991975"}},
+	RefahKBank:                            {"بانک رفاه کارگران", "Refah Kargaran Bank", "013", []string{"589463"}},
+	BankMaskan:                            {"بانک مسکن", "Bank Maskan", "014", []string{"628023"}},
+	BankSepah:                             {"بانک سپه", "Bank Sepah", "015", []string{"589210"}},
+	BankKeshavarziIran:                    {"بانک کشاورزی", "Bank Keshavarzi Iran", "016", []string{"603770", "639217"}},
+	BankMelliIran:                         {"بانک ملی ایران", "Bank Melli Iran", "017", []string{"603799"}},
+	TejaratBank:                           {"بانک تجارت", "Tejarat Bank", "018", []string{"627353", "585983"}},
+	BankSaderatIran:                       {"بانک صادرات ایران", "Bank Saderat Iran", "019", []string{"603769"}},
+	ExportDevelopmentBankOfIran:           {"بانک توسعه صادرات ایران", "Export Development Bank of Iran", "020", []string{"627648", "207177"}},
+	PostBankIran:                          {"پست بانک ایران", "Post Bank Iran", "021", []string{"627760"}},
+	ToseeTaavonBank:                       {"بانک توسعه تعاون", "Tosee Taavon Bank", "022", []string{"502908"}},
+	KarafarinBank:                         {"بانک کارآفرین", "Karafarin Bank", "053", []string{"627488", "502910"}},
+	ParsianBank:                           {"بانک پارسیان", "Parsian Bank", "054", []string{"622106", "627884", "639194"}},
+	EghtesadNovinBank:                     {"بانک اقتصاد نوین", "EN Bank", "055", []string{"627412"}},
+	SamanBank:                             {"بانک سامان", "Saman Bank", "056", []string{"621986"}},
+	PasargadBank:                          {"بانک پاسارگاد", "Bank Pasargad", "057", []string{"502229", "639347"}},
+	SarmayehBank:                          {"بانک سرمایه", "Sarmayeh Bank", "058", []string{"639607"}},
+	SinaBank:                              {"بانک سینا", "Sina Bank", "059", []string{"639346"}},
+	GharzolhasaneMehrIranBank:             {"بانک قرض‌الحسنه مهر ایران", "Gharzolhasaneh Mehr Iran Bank", "060", []string{"606373"}},
+	ShahrBank:                             {"بانک شهر", "Shahr Bank", "061", []string{"502806", "504706"}},
+	AyandehBank:                           {"بانک آینده", "Ayandeh Bank", "062", []string{"636214"}},
+	TourismBank:                           {"بانک گردشگری", "Tourism Bank", "064", []string{"505416"}},
+	DayBank:                               {"بانک دی", "Day Bank", "066", []string{"502938"}},
+	IranZaminBank:                         {"بانک ایران زمین", "Iran Zamin Bank", "069", []string{"505785"}},
+	ResalatGharzolhasaneBank:              {"بانک قرض‌الحسنه رسالت", "Resalat Gharzolhasaneh Bank", "070", []string{"504172"}},
+	MelalCreditInstitution:                {"موسسه اعتباری ملل", "Melal Credit Institution", "075", []string{"606256"}},
+	MiddleEastBank:                        {"بانک خاورمیانه", "Middle East Bank", "078", []string{"585947"}},
+	NoorCreditInstitution:                 {"موسسه اعتباری نور", "Noor Credit Institution", "080", []string{"507677"}},
+	IranVenezuelaBiNationalBank:           {"بانک مشترک ایران و ونزوئلا", "Iran-Venezuela Bi-National Bank", "095", []string{"581874"}},
+}
+
+var (
+	banksByKey      = map[string]Bank{}
+	banksByIbanCode = map[string]Bank{}
+	banksByBin      = map[string]Bank{}
+)
+
+func init() {
+	for b, info := range banks {
+		banksByKey[bankKey(string(b))] = b
+		banksByKey[bankKey(info.persianName)] = b
+		banksByKey[bankKey(info.englishName)] = b
+		banksByIbanCode[info.ibanCode] = b
+		for _, bin := range info.bins {
+			banksByBin[bin] = b
+		}
+	}
+}
+
+// ParseBank resolves a bank from its API code (MELLAT), its IBAN bank code
+// (012) or its Persian or English name, with or without the "بانک" prefix.
+func ParseBank(s string) (Bank, error) {
+	s = Normalize(s)
+	if b, ok := banksByIbanCode[s]; ok {
+		return b, nil
+	}
+	if b, ok := banksByKey[bankKey(s)]; ok {
+		return b, nil
+	}
+	return UNKNOWN, fmt.Errorf("%w: %q", ErrUnknownBank, s)
+}
+
+func (b Bank) IsKnown() bool {
+	_, ok := banks[b]
+	return ok
+}
+
+func (b Bank) PersianName() string {
+	return banks[b].persianName
+}
+
+func (b Bank) EnglishName() string {
+	return banks[b].englishName
+}
+
+// IbanCode returns the three digit code that identifies the bank inside an IBAN.
+func (b Bank) IbanCode() string {
+	return banks[b].ibanCode
+}
+
+// BinPrefixes returns the card number prefixes issued by the bank.
+func (b Bank) BinPrefixes() []string {
+	return append([]string(nil), banks[b].bins...)
+}
+
+func (b Bank) String() string {
+	return string(b)
+}
+
+func (b Bank) MarshalText() ([]byte, error) {
+	return []byte(b), nil
+}
+
+// UnmarshalText accepts anything ParseBank does. Unrecognised values are
+// kept as they are so free text from the API is not lost.
+func (b *Bank) UnmarshalText(text []byte) error {
+	v, err := ParseBank(string(text))
+	if err != nil {
+		*b = Bank(text)
+		return nil
+	}
+	*b = v
+	return nil
+}
+
+func bankKey(s string) string {
+	s = strings.NewReplacer(
+		"ي", "ی",
+		"ك", "ک",
+		" ", "",
+		"_", "",
+		"-", "",
+	).Replace(strings.ToUpper(Normalize(s)))
+	for _, prefix := range []string{"بانک", "موسسهاعتباری", "قرضالحسنه", "BANKOF", "BANK"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	for _, suffix := range []string{"ایران", "بانک", "IRAN", "BANK"} {
+		s = strings.TrimSuffix(s, suffix)
+	}
+	return s
+}
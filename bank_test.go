@@ -0,0 +1,87 @@
+package sanbod
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseBank(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Bank
+	}{
+		{"MELLAT", BankMellat},
+		{"mellat", BankMellat},
+		{"012", BankMellat},
+		{"۰۱۲", BankMellat},
+		{"بانک ملت", BankMellat},
+		{"ملت", BankMellat},
+		{"Bank Mellat", BankMellat},
+		{"بانك ملي ايران", BankMelliIran},
+		{"Post Bank Iran", PostBankIran},
+		{"SANAT_VA_MADAN", BankOfIndustryMine},
+		{"054", ParsianBank},
+		{"Parsian Bank", ParsianBank},
+	}
+	for _, tt := range tests {
+		got, err := ParseBank(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseBank(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "999", "بانک ناموجود", "NOT_A_BANK"} {
+		got, err := ParseBank(in)
+		if !errors.Is(err, ErrUnknownBank) || got != UNKNOWN {
+			t.Errorf("ParseBank(%q) = %s, %v, want UNKNOWN and ErrUnknownBank", in, got, err)
+		}
+	}
+}
+
+func TestBankMetadata(t *testing.T) {
+	for b := range banks {
+		if b.PersianName() == "" || b.EnglishName() == "" || len(b.IbanCode()) != 3 || !isDigits(b.IbanCode()) {
+			t.Errorf("incomplete metadata for %s", b)
+		}
+		for _, name := range []string{string(b), b.PersianName(), b.EnglishName(), b.IbanCode()} {
+			if got, err := ParseBank(name); err != nil || got != b {
+				t.Errorf("ParseBank(%q) = %s, %v, want %s", name, got, err, b)
+			}
+		}
+		for _, bin := range b.BinPrefixes() {
+			if len(bin) != 6 || banksByBin[bin] != b {
+				t.Errorf("bin %q of %s does not resolve back", bin, b)
+			}
+		}
+	}
+	if UNKNOWN.IsKnown() || UNKNOWN.IbanCode() != "" || !BankMellat.IsKnown() {
+		t.Error("unexpected IsKnown")
+	}
+
+	bins := BankMellat.BinPrefixes()
+	bins[0] = "000000"
+	if BankMellat.BinPrefixes()[0] == "000000" {
+		t.Error("BinPrefixes exposes the internal table")
+	}
+}
+
+func TestBankJSON(t *testing.T) {
+	var v struct {
+		Bank Bank `json:"bank"`
+	}
+	for in, want := range map[string]Bank{
+		`{"bank":"MELLAT"}`:     BankMellat,
+		`{"bank":"بانک سامان"}`: SamanBank,
+		`{"bank":"056"}`:        SamanBank,
+		`{"bank":"Some Bank"}`:  Bank("Some Bank"),
+	} {
+		if err := json.Unmarshal([]byte(in), &v); err != nil || v.Bank != want {
+			t.Errorf("decoding %s = %q, %v, want %q", in, v.Bank, err, want)
+		}
+	}
+
+	data, err := json.Marshal(map[string]Bank{"bank": TejaratBank})
+	if err != nil || string(data) != `{"bank":"TEJARAT"}` {
+		t.Errorf("encoding = %s, %v", data, err)
+	}
+}
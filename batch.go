@@ -0,0 +1,113 @@
+package sanbod
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultBatchConcurrency = 4
+
+type BatchResult[In, Out any] struct {
+	Index  int
+	Input  In
+	Output Out
+	Err    error
+	// Duplicate is set when the input repeats an earlier one and shares its result.
+	Duplicate bool
+}
+
+type BatchProgress struct {
+	Done   int
+	Failed int
+	Total  int
+}
+
+type BatchOptions struct {
+	// Concurrency caps the calls in flight, 4 when zero.
+	Concurrency int
+	// OnProgress is called, never concurrently, after each distinct input completes.
+	OnProgress func(BatchProgress)
+	// RequestOptions are passed to every call.
+	RequestOptions []RequestOption
+}
+
+// Batch calls fn once for every distinct input, at most opts.Concurrency at
+// a time, and returns one result per input in input order. Calls made
+// through a Client also wait on its RateLimiter. When ctx is cancelled the
+// results so far are returned along with ctx.Err(); inputs that never ran
+// carry ctx.Err() as their error.
+//
+//	results, err := sanbod.Batch(ctx, requests, client.InquireProfile, sanbod.BatchOptions{Concurrency: 8})
+func Batch[In comparable, Out any](ctx context.Context, inputs []In, fn func(context.Context, In, ...RequestOption) (Out, error), opts BatchOptions) ([]BatchResult[In, Out], error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make([]BatchResult[In, Out], len(inputs))
+	first := make(map[In]int, len(inputs))
+	var unique []int
+	for i, in := range inputs {
+		results[i] = BatchResult[In, Out]{Index: i, Input: in}
+		if _, ok := first[in]; ok {
+			results[i].Duplicate = true
+			continue
+		}
+		first[in] = i
+		unique = append(unique, i)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		progress = BatchProgress{Total: len(unique)}
+		started  = make([]bool, len(inputs))
+		jobs     = make(chan int)
+	)
+	for w := 0; w < min(concurrency, len(unique)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				out, err := fn(ctx, inputs[i], opts.RequestOptions...)
+				results[i].Output, results[i].Err = out, err
+
+				mu.Lock()
+				progress.Done++
+				if err != nil {
+					progress.Failed++
+				}
+				if opts.OnProgress != nil {
+					opts.OnProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, i := range unique {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+			started[i] = true
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, i := range unique {
+		if !started[i] {
+			results[i].Err = ctx.Err()
+		}
+	}
+	for i := range results {
+		if results[i].Duplicate {
+			src := results[first[results[i].Input]]
+			results[i].Output, results[i].Err = src.Output, src.Err
+		}
+	}
+
+	return results, ctx.Err()
+}
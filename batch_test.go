@@ -0,0 +1,72 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	var calls, inFlight, peak atomic.Int32
+	fn := func(ctx context.Context, n int, _ ...RequestOption) (int, error) {
+		calls.Add(1)
+		cur := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if cur <= p || peak.CompareAndSwap(p, cur) {
+				break
+			}
+		}
+		if n < 0 {
+			return 0, errors.New("negative")
+		}
+		return n * 2, nil
+	}
+
+	var last BatchProgress
+	results, err := Batch(context.Background(), []int{1, 2, 1, -1, 3, 2}, fn, BatchOptions{
+		Concurrency: 2,
+		OnProgress:  func(p BatchProgress) { last = p },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 4 || peak.Load() > 2 {
+		t.Fatalf("calls %d, peak concurrency %d", calls.Load(), peak.Load())
+	}
+	want := []int{2, 4, 2, 0, 6, 4}
+	for i, r := range results {
+		if r.Index != i || r.Output != want[i] {
+			t.Fatalf("result %d = %+v, want output %d", i, r, want[i])
+		}
+	}
+	if !results[2].Duplicate || !results[5].Duplicate || results[3].Err == nil {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if last != (BatchProgress{Done: 4, Failed: 1, Total: 4}) {
+		t.Fatalf("unexpected progress %+v", last)
+	}
+}
+
+func TestBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fn := func(ctx context.Context, n int, _ ...RequestOption) (int, error) {
+		if n == 2 {
+			cancel()
+		}
+		return n, nil
+	}
+
+	results, err := Batch(ctx, []int{1, 2, 3, 4, 5}, fn, BatchOptions{Concurrency: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if results[0].Output != 1 || results[1].Output != 2 || results[0].Err != nil {
+		t.Fatalf("finished results lost: %+v", results[:2])
+	}
+	if !errors.Is(results[4].Err, context.Canceled) {
+		t.Fatalf("expected unstarted input to carry context.Canceled, got %+v", results[4])
+	}
+}
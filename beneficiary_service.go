@@ -0,0 +1,197 @@
+package sanbod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/parparvaz/sanbod-sdk-golang/namematch"
+)
+
+const defaultBeneficiaryThreshold = 0.85
+
+type OwnerMatch struct {
+	FirstName string             `json:"first_name"`
+	LastName  string             `json:"last_name"`
+	Score     float64            `json:"score"`
+	Matched   bool               `json:"matched"`
+	Reasons   []namematch.Reason `json:"reasons"`
+}
+
+type BeneficiaryVerification struct {
+	Approved      bool                `json:"approved"`
+	Reasons       []string            `json:"reasons"`
+	DepositStatus DepositStatus       `json:"deposit_status"`
+	Owners        []OwnerMatch        `json:"owners"`
+	Threshold     float64             `json:"threshold"`
+	Iban          *IbanInquiry        `json:"iban,omitempty"`
+	Profile       *InquiryUserProfile `json:"profile,omitempty"`
+}
+
+// BeneficiaryVerificationService approves a payout destination when the IBAN
+// is active and one of its owners matches the verified person's name with a
+// score of at least the threshold.
+type BeneficiaryVerificationService struct {
+	c            *Client
+	iban         IBAN
+	nationalCode NationalCode
+	birthDate    JalaliDate
+	threshold    float64
+	matcher      *namematch.Matcher
+}
+
+func (c *Client) NewBeneficiaryVerificationService() *BeneficiaryVerificationService {
+	return &BeneficiaryVerificationService{
+		c:         c,
+		threshold: defaultBeneficiaryThreshold,
+		matcher:   namematch.New(),
+	}
+}
+
+func (s *BeneficiaryVerificationService) Iban(iban IBAN) *BeneficiaryVerificationService {
+	s.iban = iban
+	return s
+}
+
+func (s *BeneficiaryVerificationService) NationalCode(nationalCode NationalCode) *BeneficiaryVerificationService {
+	s.nationalCode = nationalCode
+	return s
+}
+
+func (s *BeneficiaryVerificationService) Birthdate(birthDate JalaliDate) *BeneficiaryVerificationService {
+	s.birthDate = birthDate
+	return s
+}
+
+// Threshold sets the minimum name similarity, between 0 and 1, for an owner to match.
+func (s *BeneficiaryVerificationService) Threshold(threshold float64) *BeneficiaryVerificationService {
+	s.threshold = threshold
+	return s
+}
+
+// Matcher replaces the name matcher, for example to change the honorifics it ignores.
+func (s *BeneficiaryVerificationService) Matcher(matcher *namematch.Matcher) *BeneficiaryVerificationService {
+	s.matcher = matcher
+	return s
+}
+
+func (s *BeneficiaryVerificationService) validated(raw bool) (in BeneficiaryVerificationService, err error) {
+	v := &validator{raw: raw}
+	in = *s
+	in.iban = parseField(v, "iban", s.iban, true, ParseIBAN)
+	in.nationalCode = parseField(v, "nationalCode", s.nationalCode, true, ParseNationalCode)
+	v.jalaliDate("birthDate", s.birthDate)
+	if s.threshold <= 0 || s.threshold > 1 {
+		v.invalid("threshold", fmt.Errorf("must be in (0, 1], got %v", s.threshold))
+	}
+	return in, v.err()
+}
+
+func (s *BeneficiaryVerificationService) Validate() error {
+	_, err := s.validated(false)
+	return err
+}
+
+func (s *BeneficiaryVerificationService) Do(ctx context.Context, opts ...RequestOption) (res *BeneficiaryVerification, err error) {
+	in, err := s.validated(!s.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	ibanOpts, profileOpts := opts, opts
+	if traceId := callerTraceId(ctx, opts); traceId != "" {
+		ibanOpts = append(opts[:len(opts):len(opts)], WithTraceId(traceId+"-iban"))
+		profileOpts = append(opts[:len(opts):len(opts)], WithTraceId(traceId+"-profile"))
+	}
+
+	var (
+		wg                  sync.WaitGroup
+		iban                *IbanInquiry
+		profile             *InquiryUserProfile
+		ibanErr, profileErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		iban, ibanErr = s.c.NewIbanInquiryService().Iban(in.iban).Do(ctx, ibanOpts...)
+	}()
+	go func() {
+		defer wg.Done()
+		profile, profileErr = s.c.NewInquiryUserProfileService().
+			NationalCode(in.nationalCode).
+			Birthdate(in.birthDate).
+			Do(ctx, profileOpts...)
+	}()
+	wg.Wait()
+
+	if ibanErr != nil {
+		return nil, ibanErr
+	}
+	if profileErr != nil {
+		return nil, profileErr
+	}
+	if err := iban.Err(); err != nil {
+		return nil, err
+	}
+	if err := profile.Err(); err != nil {
+		return nil, err
+	}
+
+	return verifyBeneficiary(iban, profile, in.matcher, in.threshold), nil
+}
+
+func verifyBeneficiary(iban *IbanInquiry, profile *InquiryUserProfile, matcher *namematch.Matcher, threshold float64) *BeneficiaryVerification {
+	res := &BeneficiaryVerification{
+		DepositStatus: iban.Message.DepositStatus,
+		Threshold:     threshold,
+		Iban:          iban,
+		Profile:       profile,
+	}
+
+	statusOK := iban.Message.DepositStatus.IsActive()
+	if statusOK {
+		res.Reasons = append(res.Reasons, "deposit is active")
+	} else {
+		res.Reasons = append(res.Reasons, fmt.Sprintf("deposit status %q is not active", iban.Message.DepositStatus))
+	}
+
+	fullName := strings.TrimSpace(profile.Message.FirstName + " " + profile.Message.LastName)
+	if fullName == "" {
+		res.Reasons = append(res.Reasons, "profile inquiry returned no name")
+	}
+	matched := false
+	for _, owner := range iban.Message.OwnersInfo {
+		m := OwnerMatch{
+			FirstName: owner.FirstName,
+			LastName:  owner.LastName,
+		}
+		ownerName := strings.TrimSpace(owner.FirstName + " " + owner.LastName)
+		if fullName == "" || ownerName == "" {
+			res.Owners = append(res.Owners, m)
+			if ownerName == "" {
+				res.Reasons = append(res.Reasons, "owner has no name")
+			}
+			continue
+		}
+
+		cmp := matcher.Compare(fullName, ownerName)
+		score := cmp.Score
+		m.Score = score
+		m.Matched = score >= threshold
+		m.Reasons = cmp.Reasons
+		res.Owners = append(res.Owners, m)
+		if m.Matched {
+			matched = true
+			res.Reasons = append(res.Reasons, fmt.Sprintf("owner %s %s matches with score %.2f %v", owner.FirstName, owner.LastName, score, cmp.Reasons))
+		} else {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("owner %s %s scores %.2f %v, below %.2f", owner.FirstName, owner.LastName, score, cmp.Reasons, threshold))
+		}
+	}
+	if len(iban.Message.OwnersInfo) == 0 {
+		res.Reasons = append(res.Reasons, "iban inquiry returned no owners")
+	}
+
+	res.Approved = statusOK && matched
+	return res
+}
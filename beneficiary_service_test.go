@@ -0,0 +1,114 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/parparvaz/sanbod-sdk-golang/namematch"
+)
+
+func TestVerifyBeneficiary(t *testing.T) {
+	matcher := namematch.New()
+	near := matcher.Compare("علی رضایی", "علی رضوانی").Score
+	if near <= 0 || near >= 1 {
+		t.Fatalf("expected a partial score for the near match, got %v", near)
+	}
+
+	ali := IbanOwner{FirstName: "علی", LastName: "رضایی"}
+	tests := []struct {
+		name         string
+		status       DepositStatus
+		owners       []IbanOwner
+		first, last  string
+		threshold    float64
+		wantApproved bool
+		wantMatched  []bool
+	}{
+		{"active exact", DepositStatusActive, []IbanOwner{ali}, "علی", "رضایی", 0.85, true, []bool{true}},
+		{"blocked with credit", DepositStatusBlockedWithCredit, []IbanOwner{ali}, "علی", "رضایی", 0.85, false, []bool{true}},
+		{"dormant", DepositStatusDormant, []IbanOwner{ali}, "علی", "رضایی", 0.85, false, []bool{true}},
+		{"unknown status", "", []IbanOwner{ali}, "علی", "رضایی", 0.85, false, []bool{true}},
+		{"joint owner matches", DepositStatusActive, []IbanOwner{{"مریم", "احمدی"}, ali}, "علی", "رضایی", 0.85, true, []bool{false, true}},
+		{"no joint owner matches", DepositStatusActive, []IbanOwner{{"مریم", "احمدی"}, {"حسن", "کریمی"}}, "علی", "رضایی", 0.85, false, []bool{false, false}},
+		{"score at threshold", DepositStatusActive, []IbanOwner{{"علی", "رضوانی"}}, "علی", "رضایی", near, true, []bool{true}},
+		{"score below threshold", DepositStatusActive, []IbanOwner{{"علی", "رضوانی"}}, "علی", "رضایی", near + 0.001, false, []bool{false}},
+		{"exact at threshold one", DepositStatusActive, []IbanOwner{ali}, "علی", "رضایی", 1, true, []bool{true}},
+		{"empty names", DepositStatusActive, []IbanOwner{{}}, "", "", 0.85, false, []bool{false}},
+		{"empty profile name", DepositStatusActive, []IbanOwner{ali}, " ", "", 0.85, false, []bool{false}},
+		{"empty owner name", DepositStatusActive, []IbanOwner{{" ", ""}}, "علی", "رضایی", 0.85, false, []bool{false}},
+		{"no owners", DepositStatusActive, nil, "علی", "رضایی", 0.85, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iban := &IbanInquiry{Message: IbanInfo{DepositStatus: tt.status, OwnersInfo: tt.owners}}
+			profile := &InquiryUserProfile{Message: PersonProfile{FirstName: tt.first, LastName: tt.last}}
+
+			res := verifyBeneficiary(iban, profile, matcher, tt.threshold)
+			if res.Approved != tt.wantApproved {
+				t.Fatalf("approved = %v, want %v: %v", res.Approved, tt.wantApproved, res.Reasons)
+			}
+			if len(res.Owners) != len(tt.wantMatched) {
+				t.Fatalf("expected %d owners, got %+v", len(tt.wantMatched), res.Owners)
+			}
+			for i, want := range tt.wantMatched {
+				if res.Owners[i].Matched != want {
+					t.Errorf("owner %d matched = %v, want %v (score %v)", i, res.Owners[i].Matched, want, res.Owners[i].Score)
+				}
+			}
+			if len(res.Reasons) == 0 {
+				t.Fatal("expected reasons for the decision")
+			}
+		})
+	}
+}
+
+func TestBeneficiaryVerificationService(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointIbanInquiry:    `{"error":false,"message":{"depositStatus":"02","ownersInfo":[{"firstName":"مریم","lastName":"احمدی"},{"firstName":"علي","lastName":"رضایی"}]}}`,
+		EndpointInquiryProfile: `{"error":false,"message":{"firstName":"علی","lastName":"رضایی"}}`,
+	})
+
+	res, err := c.NewBeneficiaryVerificationService().
+		Iban("IR820540102680020817909002").
+		NationalCode("0499370899").
+		Birthdate(JalaliDate{1370, 5, 12}).
+		Do(context.Background(), WithTraceId("payout-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Iban.RequestTraceId != "payout-1-iban" || res.Profile.RequestTraceId != "payout-1-profile" {
+		t.Fatalf("expected a traceid per inquiry, got %q and %q", res.Iban.RequestTraceId, res.Profile.RequestTraceId)
+	}
+	if !res.Approved || res.Owners[0].Matched || !res.Owners[1].Matched || len(res.Owners[1].Reasons) == 0 || res.Owners[1].Reasons[0] != namematch.ReasonArabicLetters {
+		t.Fatalf("unexpected verification %+v", res)
+	}
+	if f.calls[EndpointIbanInquiry] != 1 || f.calls[EndpointInquiryProfile] != 1 {
+		t.Fatalf("expected one call to each inquiry, got %v", f.calls)
+	}
+}
+
+func TestBeneficiaryVerificationServiceErrorEnvelopes(t *testing.T) {
+	ok := map[string]string{
+		EndpointIbanInquiry:    `{"error":false,"message":{"depositStatus":"02","ownersInfo":[{"firstName":"علی","lastName":"رضایی"}]}}`,
+		EndpointInquiryProfile: `{"error":false,"message":{"firstName":"علی","lastName":"رضایی"}}`,
+	}
+	for _, endpoint := range []string{EndpointIbanInquiry, EndpointInquiryProfile} {
+		t.Run(endpoint, func(t *testing.T) {
+			responses := maps.Clone(ok)
+			responses[endpoint] = `{"error":true,"message":{},"result_number":-5}`
+			_, c := newFakeServer(t, responses)
+
+			res, err := c.NewBeneficiaryVerificationService().
+				Iban("IR820540102680020817909002").
+				NationalCode("0499370899").
+				Birthdate(JalaliDate{1370, 5, 12}).
+				Do(context.Background())
+			var aPIError *APIError
+			if res != nil || !errors.As(err, &aPIError) || aPIError.ResultNumber != -5 {
+				t.Fatalf("expected the error envelope as an error, got %+v and %v", res, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+// Package bulk runs a sanbod task over every row of a CSV or JSONL file and
+// writes the rows back out with the results added. Finished rows are
+// recorded in a checkpoint file, so a run that stops half way can be started
+// again without calling the api for them a second time.
+package bulk
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/parparvaz/sanbod-sdk-golang"
+)
+
+const (
+	ColumnStatus = "status"
+	ColumnError  = "error"
+
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+)
+
+const defaultChunkSize = 100
+
+type Config struct {
+	Input  string
+	Output string
+	// Checkpoint is the file finished rows are recorded in, Output +
+	// ".checkpoint" when empty. It holds row hashes only.
+	Checkpoint string
+	// InputFormat and OutputFormat default to the file extensions; an
+	// output without a known extension is written like the input.
+	InputFormat  Format
+	OutputFormat Format
+	// ChunkSize is how many rows are handed to sanbod.Batch at a time, and
+	// so how often OnProgress is called, 100 when zero. Rows are written
+	// and checkpointed one by one as they finish either way.
+	ChunkSize   int
+	Concurrency int
+	// OnProgress is called after every chunk.
+	OnProgress     func(Summary)
+	RequestOptions []sanbod.RequestOption
+}
+
+type Summary struct {
+	Rows int `json:"rows"`
+	// Skipped rows were finished by an earlier run.
+	Skipped   int `json:"skipped"`
+	Succeeded int `json:"succeeded"`
+	// Failed rows are written with status failed and are not retried.
+	Failed int `json:"failed"`
+	// Pending rows hit a retryable error or were cut off by cancellation;
+	// they are neither written nor checkpointed, so the next run retries them.
+	Pending int `json:"pending"`
+}
+
+type pendingRow struct {
+	key string
+	row Row
+}
+
+// Run applies task to every row of cfg.Input not yet in the checkpoint,
+// appending finished rows to cfg.Output. Rows are written in the order
+// their calls finish, with ColumnStatus and ColumnError added to the task's
+// outputs.
+func Run(ctx context.Context, api sanbod.API, task Task, cfg Config) (Summary, error) {
+	var summary Summary
+
+	inFormat, err := formatOf(cfg.Input, cfg.InputFormat)
+	if err != nil {
+		return summary, err
+	}
+	outFormat, err := formatOf(cfg.Output, cfg.OutputFormat)
+	if err != nil {
+		outFormat = inFormat
+	}
+	checkpointPath := cfg.Checkpoint
+	if checkpointPath == "" {
+		checkpointPath = cfg.Output + ".checkpoint"
+	}
+	chunkSize := cfg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	in, err := os.Open(cfg.Input)
+	if err != nil {
+		return summary, err
+	}
+	defer in.Close()
+
+	reader, err := newRowReader(bufio.NewReader(in), inFormat)
+	if err != nil {
+		return summary, err
+	}
+
+	done, err := readCheckpoint(checkpointPath)
+	if err != nil {
+		return summary, err
+	}
+	checkpoint, err := os.OpenFile(checkpointPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return summary, err
+	}
+	defer checkpoint.Close()
+
+	out, err := os.OpenFile(cfg.Output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return summary, err
+	}
+	defer out.Close()
+
+	stat, err := out.Stat()
+	if err != nil {
+		return summary, err
+	}
+	var writer rowWriter
+	// the writer is created with the first chunk, when the columns of a
+	// JSONL input are known
+	newWriter := func() (rowWriter, error) {
+		columns := slices.Clone(reader.Columns())
+		for _, col := range append(slices.Clone(task.Outputs), ColumnStatus, ColumnError) {
+			if !slices.Contains(columns, col) {
+				columns = append(columns, col)
+			}
+		}
+		return newRowWriter(out, outFormat, columns, stat.Size() == 0)
+	}
+
+	flush := func(chunk []pendingRow) error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		if writer == nil {
+			writer, err = newWriter()
+			if err != nil {
+				return err
+			}
+		}
+		runErr := runChunk(ctx, api, task, cfg, chunk, writer, checkpoint, out, &summary)
+		if cfg.OnProgress != nil {
+			cfg.OnProgress(summary)
+		}
+		return runErr
+	}
+
+	var chunk []pendingRow
+	for n := 1; ; n++ {
+		row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return summary, err
+		}
+		summary.Rows++
+
+		key := rowKey(n, row)
+		if _, ok := done[key]; ok {
+			summary.Skipped++
+			continue
+		}
+		chunk = append(chunk, pendingRow{key: key, row: row})
+		if len(chunk) < chunkSize {
+			continue
+		}
+		err = flush(chunk)
+		if err != nil {
+			return summary, err
+		}
+		chunk = chunk[:0]
+	}
+
+	return summary, flush(chunk)
+}
+
+// runChunk runs one chunk through sanbod.Batch. A single goroutine takes
+// each row as its call returns, writes it to the output and syncs it, then
+// records it in the checkpoint, so a crash loses no row the api has already
+// answered. A crash between the two syncs repeats that one row in the output
+// on resume; rows whose calls are still in flight are called again.
+func runChunk(ctx context.Context, api sanbod.API, task Task, cfg Config, chunk []pendingRow, writer rowWriter, checkpoint, out *os.File, summary *Summary) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type finished struct {
+		p      pendingRow
+		output Row
+		err    error
+	}
+	var (
+		finishedRows = make(chan finished, len(chunk))
+		writeErr     = make(chan error, 1)
+		succeeded    int
+		failed       int
+	)
+	go func() {
+		var err error
+		for f := range finishedRows {
+			if err != nil || f.err != nil && (sanbod.IsRetryable(f.err) || errors.Is(f.err, context.Canceled)) {
+				continue
+			}
+			err = writeRow(f.p, f.output, f.err, writer, checkpoint, out)
+			if err != nil {
+				cancel()
+				continue
+			}
+			if f.err != nil {
+				failed++
+			} else {
+				succeeded++
+			}
+		}
+		writeErr <- err
+	}()
+
+	indexes := make([]int, len(chunk))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	_, batchErr := sanbod.Batch(ctx, indexes, func(ctx context.Context, i int, opts ...sanbod.RequestOption) (Row, error) {
+		output, err := task.Run(ctx, api, chunk[i].row, opts...)
+		finishedRows <- finished{chunk[i], output, err}
+		return output, err
+	}, sanbod.BatchOptions{
+		Concurrency:    cfg.Concurrency,
+		RequestOptions: cfg.RequestOptions,
+	})
+	close(finishedRows)
+	err := <-writeErr
+
+	// rows that were not written, whether retryable, cut off or never
+	// started, are left for the next run
+	summary.Succeeded += succeeded
+	summary.Failed += failed
+	summary.Pending += len(chunk) - succeeded - failed
+	if err != nil {
+		return err
+	}
+	return batchErr
+}
+
+func writeRow(p pendingRow, output Row, taskErr error, writer rowWriter, checkpoint, out *os.File) error {
+	row := make(Row, len(p.row)+len(output)+2)
+	for k, v := range p.row {
+		row[k] = v
+	}
+	for k, v := range output {
+		row[k] = v
+	}
+	if taskErr != nil {
+		row[ColumnStatus], row[ColumnError] = StatusFailed, taskErr.Error()
+	} else {
+		row[ColumnStatus], row[ColumnError] = StatusOK, ""
+	}
+
+	err := writer.Write(row)
+	if err != nil {
+		return err
+	}
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+	err = out.Sync()
+	if err != nil {
+		return err
+	}
+
+	_, err = checkpoint.WriteString(p.key + "\n")
+	if err != nil {
+		return err
+	}
+	return checkpoint.Sync()
+}
+
+func readCheckpoint(path string) (map[string]struct{}, error) {
+	done := make(map[string]struct{})
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// a torn last line from a crash is not a valid key and is ignored
+		if len(s.Text()) == sha256.Size*2 {
+			done[s.Text()] = struct{}{}
+		}
+	}
+	return done, s.Err()
+}
+
+// rowKey identifies a row by its position and contents, so that a changed
+// input file is not mistaken for the one checkpointed.
+func rowKey(n int, row Row) string {
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	h := sha256.New()
+	h.Write([]byte(strconv.Itoa(n)))
+	for _, col := range cols {
+		h.Write([]byte{0})
+		h.Write([]byte(col))
+		h.Write([]byte{0})
+		h.Write([]byte(row[col]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
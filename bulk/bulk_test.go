@@ -0,0 +1,234 @@
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/parparvaz/sanbod-sdk-golang"
+	"github.com/parparvaz/sanbod-sdk-golang/sanbodmock"
+)
+
+func TestRunResumesCSV(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.csv")
+	output := filepath.Join(dir, "out.csv")
+	err := os.WriteFile(input, []byte("id,nid,mobile\n1,0499370899,09121234567\n2,0499370899,09351234567\n3,0499370899,0912\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var down atomic.Bool
+	down.Store(true)
+	var calls atomic.Int32
+	api := &sanbodmock.API{
+		MatchMobileFunc: func(ctx context.Context, req sanbod.MobileMatchRequest) (*sanbod.MatchNationalCodeWithMobileNumber, error) {
+			calls.Add(1)
+			switch {
+			case req.MobileNumber == "0912":
+				return nil, &sanbod.ValidationError{Fields: []sanbod.FieldError{{Field: "mobileNumber", Reason: sanbod.ReasonFormat}}}
+			case req.MobileNumber == "09351234567" && down.Load():
+				return nil, &sanbod.APIError{StatusCode: http.StatusBadGateway}
+			}
+			res := new(sanbod.MatchNationalCodeWithMobileNumber)
+			res.Message.IsMatched = true
+			res.RequestTraceId = "trace-" + string(req.MobileNumber)
+			return res, nil
+		},
+	}
+	task := MatchMobile(Columns{NationalCode: "nid", MobileNumber: "mobile"})
+	cfg := Config{Input: input, Output: output, ChunkSize: 2}
+
+	summary, err := Run(context.Background(), api, task, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != (Summary{Rows: 3, Succeeded: 1, Failed: 1, Pending: 1}) {
+		t.Fatalf("unexpected first run summary %+v", summary)
+	}
+
+	down.Store(false)
+	calls.Store(0)
+	summary, err = Run(context.Background(), api, task, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != (Summary{Rows: 3, Skipped: 2, Succeeded: 1}) || calls.Load() != 1 {
+		t.Fatalf("unexpected second run summary %+v after %d calls", summary, calls.Load())
+	}
+
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(records[0], ",") != "id,nid,mobile,is_matched,trace_id,status,error" {
+		t.Fatalf("unexpected header %v", records[0])
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected 3 rows, got %v", records[1:])
+	}
+	got := map[string][]string{}
+	for _, rec := range records[1:] {
+		got[rec[0]] = rec
+	}
+	if got["1"][5] != StatusOK || got["2"][5] != StatusOK || got["3"][5] != StatusFailed || got["2"][4] != "trace-09351234567" {
+		t.Fatalf("unexpected rows %v", records[1:])
+	}
+
+	checkpoint, err := os.ReadFile(output + ".checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(checkpoint), "0499370899") {
+		t.Fatal("checkpoint leaks row contents")
+	}
+}
+
+func TestRunJSONL(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.jsonl")
+	output := filepath.Join(dir, "out.jsonl")
+	err := os.WriteFile(input, []byte(`{"national_code":"0499370899","birth_date":"1370/01/01"}`+"\n"+`{"national_code":"0499370899","birth_date":"1370/13/01"}`+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := &sanbodmock.API{
+		InquireProfileFunc: func(ctx context.Context, req sanbod.ProfileRequest) (*sanbod.InquiryUserProfile, error) {
+			res := new(sanbod.InquiryUserProfile)
+			res.Message.FirstName = "علی"
+			return res, nil
+		},
+	}
+
+	summary, err := Run(context.Background(), api, InquireProfile(Columns{}), Config{Input: input, Output: output})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != (Summary{Rows: 2, Succeeded: 1, Failed: 1}) || len(api.Calls()) != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var first, second Row
+	if json.Unmarshal([]byte(lines[0]), &first) != nil || json.Unmarshal([]byte(lines[1]), &second) != nil {
+		t.Fatalf("unexpected output %s", data)
+	}
+	// rows are written as they finish, not in input order
+	if first[ColumnStatus] == StatusFailed {
+		first, second = second, first
+	}
+	if first["first_name"] != "علی" || first[ColumnStatus] != StatusOK || second[ColumnStatus] != StatusFailed || !strings.Contains(second[ColumnError], "birth_date") {
+		t.Fatalf("unexpected output %s", data)
+	}
+}
+
+func TestRunResumesAfterCrashMidChunk(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.csv")
+	output := filepath.Join(dir, "out.csv")
+	checkpointPath := output + ".checkpoint"
+	err := os.WriteFile(input, []byte("nid,mobile\n0499370899,09120000001\n0499370899,09120000002\n0499370899,09120000003\n0499370899,09120000004\n0499370899,09120000005\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fourth call snapshots the files once the first three rows are
+	// checkpointed, as if the process were killed right then; nothing after
+	// that reaches the api
+	var (
+		crashed, dead      atomic.Bool
+		snapOut, snapCheck []byte
+	)
+	calls := map[string]int{}
+	api := &sanbodmock.API{
+		MatchMobileFunc: func(ctx context.Context, req sanbod.MobileMatchRequest) (*sanbod.MatchNationalCodeWithMobileNumber, error) {
+			if dead.Load() {
+				return nil, context.Canceled
+			}
+			calls[string(req.MobileNumber)]++
+			if req.MobileNumber == "09120000004" && !crashed.Load() {
+				deadline := time.Now().Add(5 * time.Second)
+				for {
+					data, _ := os.ReadFile(checkpointPath)
+					if strings.Count(string(data), "\n") == 3 {
+						break
+					}
+					if time.Now().After(deadline) {
+						t.Error("finished rows were not checkpointed before the chunk ended")
+						break
+					}
+					time.Sleep(time.Millisecond)
+				}
+				snapOut, _ = os.ReadFile(output)
+				snapCheck, _ = os.ReadFile(checkpointPath)
+				crashed.Store(true)
+				dead.Store(true)
+				return nil, context.Canceled
+			}
+			res := new(sanbod.MatchNationalCodeWithMobileNumber)
+			res.Message.IsMatched = true
+			return res, nil
+		},
+	}
+	task := MatchMobile(Columns{NationalCode: "nid", MobileNumber: "mobile"})
+	cfg := Config{Input: input, Output: output, Concurrency: 1}
+
+	_, err = Run(context.Background(), api, task, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(output, snapOut, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(checkpointPath, snapCheck, 0600); err != nil {
+		t.Fatal(err)
+	}
+	dead.Store(false)
+
+	summary, err := Run(context.Background(), api, task, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != (Summary{Rows: 5, Skipped: 3, Succeeded: 2}) {
+		t.Fatalf("unexpected resumed summary %+v", summary)
+	}
+	for mobile, n := range calls {
+		want := 1
+		if mobile == "09120000004" {
+			want = 2
+		}
+		if n != want {
+			t.Fatalf("%s called %d times, want %d: %v", mobile, n, want, calls)
+		}
+	}
+
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 {
+		t.Fatalf("expected 5 rows without duplicates, got %v", records[1:])
+	}
+}
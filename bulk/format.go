@@ -0,0 +1,187 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Format int
+
+const (
+	// FormatAuto picks the format from the file extension.
+	FormatAuto Format = iota
+	FormatCSV
+	FormatJSONL
+)
+
+var ErrUnknownFormat = errors.New("bulk: unknown file format")
+
+func formatOf(path string, f Format) (Format, error) {
+	if f != FormatAuto {
+		return f, nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	}
+	return FormatAuto, fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+}
+
+// Row is one input record keyed by column name. Tasks read their inputs
+// from it and return the columns they add.
+type Row map[string]string
+
+type rowReader interface {
+	// Columns returns the input columns, known for JSONL only once a row
+	// has been read.
+	Columns() []string
+	// Next returns io.EOF after the last row.
+	Next() (Row, error)
+}
+
+func newRowReader(r io.Reader, f Format) (rowReader, error) {
+	if f == FormatCSV {
+		return newCSVReader(r)
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &jsonlReader{dec: dec}, nil
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header []string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	return &csvReader{r: cr, header: header}, nil
+}
+
+func (r *csvReader) Columns() []string {
+	return r.header
+}
+
+func (r *csvReader) Next() (Row, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	row := make(Row, len(r.header))
+	for i, col := range r.header {
+		row[col] = record[i]
+	}
+	return row, nil
+}
+
+type jsonlReader struct {
+	dec     *json.Decoder
+	columns []string
+}
+
+func (r *jsonlReader) Columns() []string {
+	return r.columns
+}
+
+func (r *jsonlReader) Next() (Row, error) {
+	var obj map[string]any
+	err := r.dec.Decode(&obj)
+	if err != nil {
+		return nil, err
+	}
+	row := make(Row, len(obj))
+	for k, v := range obj {
+		row[k], err = jsonText(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.columns == nil {
+		for k := range row {
+			r.columns = append(r.columns, k)
+		}
+		sort.Strings(r.columns)
+	}
+	return row, nil
+}
+
+func jsonText(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+type rowWriter interface {
+	Write(Row) error
+	Flush() error
+}
+
+func newRowWriter(w io.Writer, f Format, columns []string, writeHeader bool) (rowWriter, error) {
+	if f == FormatJSONL {
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	}
+	cw := &csvWriter{w: csv.NewWriter(w), columns: columns}
+	if writeHeader {
+		err := cw.w.Write(columns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cw, nil
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (w *csvWriter) Write(row Row) error {
+	record := make([]string, len(w.columns))
+	for i, col := range w.columns {
+		record[i] = row[col]
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(row Row) error {
+	return w.enc.Encode(row)
+}
+
+func (w *jsonlWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,143 @@
+package bulk
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/parparvaz/sanbod-sdk-golang"
+)
+
+// Task calls the api for one row and returns the columns it adds.
+type Task struct {
+	// Outputs lists the columns Run adds, in the order they are written to CSV.
+	Outputs []string
+	Run     func(ctx context.Context, api sanbod.API, row Row, opts ...sanbod.RequestOption) (Row, error)
+}
+
+// Columns names the input columns that hold each identifier. Empty names
+// fall back to DefaultColumns.
+type Columns struct {
+	NationalCode string
+	BirthDate    string
+	MobileNumber string
+	CardNumber   string
+}
+
+var DefaultColumns = Columns{
+	NationalCode: "national_code",
+	BirthDate:    "birth_date",
+	MobileNumber: "mobile_number",
+	CardNumber:   "card_number",
+}
+
+func (c Columns) withDefaults() Columns {
+	if c.NationalCode == "" {
+		c.NationalCode = DefaultColumns.NationalCode
+	}
+	if c.BirthDate == "" {
+		c.BirthDate = DefaultColumns.BirthDate
+	}
+	if c.MobileNumber == "" {
+		c.MobileNumber = DefaultColumns.MobileNumber
+	}
+	if c.CardNumber == "" {
+		c.CardNumber = DefaultColumns.CardNumber
+	}
+	return c
+}
+
+// InquireProfile runs InquiryUserProfileService on the national code and
+// birth date columns.
+func InquireProfile(cols Columns) Task {
+	cols = cols.withDefaults()
+	return Task{
+		Outputs: []string{"first_name", "last_name", "father_name", "birth_place", "gender", "death_status", "trace_id"},
+		Run: func(ctx context.Context, api sanbod.API, row Row, opts ...sanbod.RequestOption) (Row, error) {
+			birthDate, err := parseBirthDate(cols.BirthDate, row[cols.BirthDate])
+			if err != nil {
+				return nil, err
+			}
+			res, err := api.InquireProfile(ctx, sanbod.ProfileRequest{
+				NationalCode: sanbod.NationalCode(row[cols.NationalCode]),
+				BirthDate:    birthDate,
+			}, opts...)
+			if err != nil {
+				return nil, err
+			}
+			p := res.Message
+			return Row{
+				"first_name":   p.FirstName,
+				"last_name":    p.LastName,
+				"father_name":  p.FatherName,
+				"birth_place":  p.BirthPlace,
+				"gender":       p.Gender.String(),
+				"death_status": p.DeathStatus.String(),
+				"trace_id":     res.RequestTraceId,
+			}, nil
+		},
+	}
+}
+
+// MatchMobile runs MatchNationalCodeWithMobileNumberService on the national
+// code and mobile number columns.
+func MatchMobile(cols Columns) Task {
+	cols = cols.withDefaults()
+	return Task{
+		Outputs: []string{"is_matched", "trace_id"},
+		Run: func(ctx context.Context, api sanbod.API, row Row, opts ...sanbod.RequestOption) (Row, error) {
+			res, err := api.MatchMobile(ctx, sanbod.MobileMatchRequest{
+				NationalCode: sanbod.NationalCode(row[cols.NationalCode]),
+				MobileNumber: sanbod.MobileNumber(row[cols.MobileNumber]),
+			}, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return matchRow(res), nil
+		},
+	}
+}
+
+// MatchCard runs MatchNationalCodeWithCardNumberService on the national
+// code and card number columns, and the mobile number column when present.
+func MatchCard(cols Columns) Task {
+	cols = cols.withDefaults()
+	return Task{
+		Outputs: []string{"is_matched", "trace_id"},
+		Run: func(ctx context.Context, api sanbod.API, row Row, opts ...sanbod.RequestOption) (Row, error) {
+			res, err := api.MatchCard(ctx, sanbod.CardMatchRequest{
+				NationalCode: sanbod.NationalCode(row[cols.NationalCode]),
+				CardNumber:   sanbod.CardNumber(row[cols.CardNumber]),
+				MobileNumber: sanbod.MobileNumber(row[cols.MobileNumber]),
+			}, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return matchRow(res), nil
+		},
+	}
+}
+
+func matchRow(res *sanbod.Response[sanbod.MatchResult]) Row {
+	return Row{
+		"is_matched": strconv.FormatBool(res.Message.IsMatched),
+		"trace_id":   res.RequestTraceId,
+	}
+}
+
+// parseBirthDate reports a bad date as a validation error so the row is
+// treated as final rather than retried.
+func parseBirthDate(column, s string) (sanbod.JalaliDate, error) {
+	if s == "" {
+		return sanbod.JalaliDate{}, nil
+	}
+	d, err := sanbod.ParseJalaliDate(s)
+	if err != nil {
+		return d, &sanbod.ValidationError{Fields: []sanbod.FieldError{{
+			Field:   column,
+			Reason:  sanbod.ReasonFormat,
+			Message: err.Error(),
+			Err:     err,
+		}}}
+	}
+	return d, nil
+}
@@ -0,0 +1,76 @@
+package sanbod
+
+import (
+	"fmt"
+	"strings"
+)
+
+type CardNumber string
+
+func ParseCardNumber(s string) (CardNumber, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, Normalize(s))
+
+	if len(s) != 16 || !isDigits(s) {
+		return "", fmt.Errorf("%w: must be 16 digits", ErrInvalidCardNumber)
+	}
+	if !luhn(s) {
+		return "", fmt.Errorf("%w: %w", ErrInvalidCardNumber, ErrInvalidChecksum)
+	}
+
+	return CardNumber(s), nil
+}
+
+func (c CardNumber) Validate() error {
+	_, err := ParseCardNumber(string(c))
+	return err
+}
+
+// Bin returns the six digit issuer prefix of the card.
+func (c CardNumber) Bin() string {
+	if len(c) < 6 {
+		return ""
+	}
+	return string(c[:6])
+}
+
+// Bank resolves the issuing bank from the card prefix, UNKNOWN if the prefix is not listed.
+func (c CardNumber) Bank() Bank {
+	if bank, ok := banksByBin[c.Bin()]; ok {
+		return bank
+	}
+	return UNKNOWN
+}
+
+// String masks the middle digits so card numbers never end up in logs in full.
+func (c CardNumber) String() string {
+	if len(c) < 10 {
+		return strings.Repeat("*", len(c))
+	}
+	return string(c[:6]) + strings.Repeat("*", len(c)-10) + string(c[len(c)-4:])
+}
+
+func (c CardNumber) GoString() string {
+	return fmt.Sprintf("%q", c.String())
+}
+
+func luhn(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,55 @@
+package sanbod
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseCardNumber(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     CardNumber
+		wantErr  error
+		wantBank Bank
+	}{
+		{"6104331234567890", "6104331234567890", nil, BankMellat},
+		{"6219-8612-3456-7898", "6219861234567898", nil, SamanBank},
+		{"6037 9912 3456 7893", "6037991234567893", nil, BankMelliIran},
+		{"۵۸۹۲۱۰۱۲۳۴۵۶۷۸۹۵", "5892101234567895", nil, BankSepah},
+		{"1234567812345670", "1234567812345670", nil, UNKNOWN},
+		{"6219861234567890", "", ErrInvalidChecksum, ""},
+		{"621986123456789", "", ErrInvalidCardNumber, ""},
+		{"62198612345678988", "", ErrInvalidCardNumber, ""},
+		{"6219x61234567898", "", ErrInvalidCardNumber, ""},
+		{"", "", ErrInvalidCardNumber, ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseCardNumber(tt.in)
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+			t.Errorf("ParseCardNumber(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCardNumber(%q) = %q, want %q", tt.in, string(got), string(tt.want))
+		}
+		if err == nil && got.Bank() != tt.wantBank {
+			t.Errorf("ParseCardNumber(%q).Bank() = %s, want %s", tt.in, got.Bank(), tt.wantBank)
+		}
+	}
+}
+
+func TestCardNumberMasking(t *testing.T) {
+	c := CardNumber("6219861234567898")
+	for _, got := range []string{c.String(), fmt.Sprint(c), fmt.Sprintf("%v", c), fmt.Sprintf("%#v", c)} {
+		if got != "621986******7898" && got != `"621986******7898"` {
+			t.Errorf("card number printed as %s", got)
+		}
+	}
+	if got := CardNumber("12345").String(); got != "*****" {
+		t.Errorf("short input printed as %s", got)
+	}
+	if c.Bin() != "621986" || CardNumber("123").Bin() != "" {
+		t.Errorf("unexpected bin %q", c.Bin())
+	}
+}
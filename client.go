@@ -5,7 +5,6 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
-	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 	"io"
 	"log"
@@ -13,6 +12,8 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -24,6 +25,20 @@ const (
 	CacheRefreshToken string = "refresh_token"
 )
 
+// Endpoints of the inquiry services, as used by EnableCoalescing and ResponseCache.TTL.
+const (
+	EndpointMatchMobile             = "/sanboom/v1/infomatching/mobilenationalid"
+	EndpointMatchCard               = "/sanboom/v1/infomatching/cardnationalid"
+	EndpointInquiryProfile          = "/sanboom/v1/infoinquiry/personal"
+	EndpointInquiryProfileWithImage = "/sanboom/v1/infoinquiry/personalwithimage"
+	EndpointCitizenshipVerification = "/sanboom/v1/infoinquiry/citizenshipverification"
+	EndpointIbanInquiry             = "/sanboom/v1/banksinquiry/ibaninquiry"
+	EndpointCardToDeposit           = "/sanboom/v1/banksinquiry/cardtodeposit"
+	EndpointCardToIban              = "/sanboom/v1/banksinquiry/cardtoiban"
+	EndpointDepositToIban           = "/sanboom/v1/banksinquiry/deposittoiban"
+	EndpointIbanToDeposit           = "/banks/v1/ibantodeposit"
+)
+
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
 func NewClient(username, password string) *Client {
@@ -35,6 +50,7 @@ func NewClient(username, password string) *Client {
 		HTTPClient: http.DefaultClient,
 		Logger:     log.New(os.Stderr, "Sanbod-golang ", log.LstdFlags),
 		cache:      newCache(),
+		keySecret:  newKeySecret(),
 	}
 }
 
@@ -55,8 +71,9 @@ func NewProxyClient(username, password, proxyUrl string) *Client {
 		HTTPClient: &http.Client{
 			Transport: tr,
 		},
-		Logger: log.New(os.Stderr, "Sanbod-golang ", log.LstdFlags),
-		cache:  newCache(),
+		Logger:    log.New(os.Stderr, "Sanbod-golang ", log.LstdFlags),
+		cache:     newCache(),
+		keySecret: newKeySecret(),
 	}
 }
 
@@ -70,9 +87,21 @@ type Client struct {
 	HTTPClient *http.Client
 	Debug      bool
 	Logger     *log.Logger
-	TimeOffset int64
-	do         doFunc
-	cache      *cache
+	// DisableNormalization turns off Normalize for every request of this client.
+	DisableNormalization bool
+	// TraceIdGenerator, when set, supplies the traceid of calls that get none
+	// from WithTraceId or the context.
+	TraceIdGenerator func() string
+	// RateLimiter, when set, is waited on before each request.
+	RateLimiter RateLimiter
+	// ResponseCache, when set, serves repeated inquiries without calling the api.
+	ResponseCache *ResponseCache
+	TimeOffset    int64
+	do            doFunc
+	cache         *cache
+	authMu        sync.Mutex
+	flights       flightGroup
+	keySecret     []byte
 }
 
 func (c *Client) debug(format string, v ...interface{}) {
@@ -82,7 +111,7 @@ func (c *Client) debug(format string, v ...interface{}) {
 	}
 }
 
-func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
+func (c *Client) parseRequest(ctx context.Context, r *request, opts ...RequestOption) (err error) {
 	for _, opt := range opts {
 		opt(r)
 	}
@@ -91,7 +120,8 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 		return err
 	}
 
-	r.query.Set("traceid", uuid.New().String())
+	r.traceId = c.traceId(ctx, r)
+	r.query.Set("traceid", r.traceId)
 	fullURL := fmt.Sprintf("%s%s", c.BaseURL, r.endpoint)
 	header := http.Header{}
 	if r.header != nil {
@@ -113,6 +143,21 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 		header.Add("Authorization", "Bearer "+a)
 	}
 
+	normalize := !r.rawParams && !c.DisableNormalization
+	if normalize {
+		r.query = normalizeValues(r.query)
+		r.form = normalizeValues(r.form)
+	}
+	if r.jsonParams != nil {
+		if normalize {
+			r.jsonParams = normalizeParams(r.jsonParams)
+		}
+		r.json, err = json.Marshal(r.jsonParams)
+		if err != nil {
+			return err
+		}
+	}
+
 	queryString := r.query.Encode()
 	bodyString := r.form.Encode()
 	if bodyString != "" {
@@ -127,7 +172,7 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 		fullURL = fmt.Sprintf("%s?%s", fullURL, queryString)
 	}
 
-	c.debug("full url: %s, body: %s", fullURL, bodyString)
+	c.debug("full url: %s, body: %s", fullURL, r.sentBody())
 	r.fullURL = fullURL
 	r.header = header
 	r.body = body
@@ -135,7 +180,17 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 }
 
 func (c *Client) getAuth() {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if _, ok := c.cache.get(CacheAccessToken); ok {
+		return
+	}
+
 	newClient := NewClient(c.Username, c.Password)
+	newClient.BaseURL = c.BaseURL
+	newClient.HTTPClient = c.HTTPClient
+	newClient.do = c.do
 
 	res, err := newClient.NewCCTokenService().
 		Scope([]string{
@@ -157,11 +212,15 @@ func (c *Client) getAuth() {
 
 func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption) (data []byte, err error) {
 
-	err = c.parseRequest(r, opts...)
+	err = c.parseRequest(ctx, r, opts...)
 	if err != nil {
 		return []byte{}, err
 	}
 
+	return c.coalesced(ctx, r)
+}
+
+func (c *Client) send(ctx context.Context, r *request) (data []byte, err error) {
 	req, err := http.NewRequest(r.method, r.fullURL, r.body)
 	if err != nil {
 		return []byte{}, err
@@ -180,14 +239,25 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 		f = c.HTTPClient.Do
 	}
 
+	if c.RateLimiter != nil {
+		err = c.RateLimiter.Wait(ctx)
+		if err != nil {
+			return []byte{}, err
+		}
+	}
+
+	started := time.Now()
 	res, err := f(req)
 	if err != nil {
-		return []byte{}, err
+		r.metadata.record(r, nil, started)
+		return []byte{}, fmt.Errorf("sanbod: traceid=%s: %w", r.traceId, err)
 	}
 
 	data, err = io.ReadAll(res.Body)
+	r.metadata.record(r, res, started)
 	if err != nil {
-		return []byte{}, err
+		_ = res.Body.Close()
+		return []byte{}, fmt.Errorf("sanbod: traceid=%s: %w", r.traceId, err)
 	}
 
 	defer func() {
@@ -197,13 +267,13 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 		}
 	}()
 
-	c.debug("response: %#v", res)
+	c.debug("traceid: %s, response: %#v", r.traceId, res)
 	c.debug("response body: %s", string(data))
 	c.debug("response status code: %d", res.StatusCode)
 
 	if res.StatusCode >= http.StatusBadRequest {
 
-		apiErr := new(APIError)
+		apiErr := &APIError{StatusCode: res.StatusCode, RequestTraceId: r.traceId}
 		e := json.Unmarshal(data, apiErr)
 		if e != nil {
 			c.debug("failed to unmarshal json: %s", e)
@@ -239,38 +309,24 @@ func (c *Client) NewInquiryUserProfileWithImageService() *InquiryUserProfileWith
 func (c *Client) NewInquiryUserProfileService() *InquiryUserProfileService {
 	return &InquiryUserProfileService{c: c}
 }
-
-const (
-	CentralBankOfTheIslamicRepublicOfIran string = "MARKAZI"
-	BankOfIndustryMine                    string = "SANAT_VA_MADAN"
-	BankMellat                            string = "MELLAT"
-	RefahKBank                            string = "REFAH"
-	BankMaskan                            string = "MASKAN"
-	BankSepah                             string = "SEPAH"
-	BankKeshavarziIran                    string = "KESHAVARZI"
-	BankMelliIran                         string = "MELLI"
-	TejaratBank                           string = "TEJARAT"
-	BankSaderatIran                       string = "SADERAT"
-	ExportDevelopmentBankOfIran           string = "TOSEAH_SADERAT"
-	PostBankIran                          string = "POST"
-	ToseeTaavonBank                       string = "TOSEAH_TAAVON"
-	KarafarinBank                         string = "KARAFARIN"
-	ParsianBank                           string = "PARSIAN"
-	EghtesadNovinBank                     string = "EGHTESAD_NOVIN"
-	SamanBank                             string = "SAMAN"
-	PasargadBank                          string = "PASARGAD"
-	SarmayehBank                          string = "SARMAYEH"
-	SinaBank                              string = "SINA"
-	GharzolhasaneMehrIranBank             string = "MEHR_IRAN"
-	ShahrBank                             string = "SHAHR"
-	AyandehBank                           string = "AYANDEH"
-	TourismBank                           string = "GARDESHGARI"
-	DayBank                               string = "DAY"
-	IranZaminBank                         string = "IRANZAMIN"
-	ResalatGharzolhasaneBank              string = "RESALAT"
-	MelalCreditInstitution                string = "MELAL"
-	MiddleEastBank                        string = "KHAVARMIANEH"
-	NoorCreditInstitution                 string = "NOOR"
-	IranVenezuelaBiNationalBank           string = "IRAN_VENEZUELA"
-	UNKNOWN                               string = "UNKNOWN"
-)
+func (c *Client) NewCitizenshipVerificationService() *CitizenshipVerificationService {
+	return &CitizenshipVerificationService{c: c}
+}
+func (c *Client) NewRevokeTokenService() *RevokeTokenService {
+	return &RevokeTokenService{c: c}
+}
+func (c *Client) NewCardToAccountNumberService() *CardToAccountNumberService {
+	return &CardToAccountNumberService{c: c}
+}
+func (c *Client) NewCardToIbanService() *CardToIbanService {
+	return &CardToIbanService{c: c}
+}
+func (c *Client) NewAccountNumberToIbanService() *AccountNumberToIbanService {
+	return &AccountNumberToIbanService{c: c}
+}
+func (c *Client) NewIbanToAccountNumberService() *IbanToAccountNumberService {
+	return &IbanToAccountNumberService{c: c}
+}
+func (c *Client) NewIbanInquiryService() *IbanInquiryService {
+	return &IbanInquiryService{c: c}
+}
@@ -0,0 +1,368 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// fakeServer serves the token endpoint and the given handlers, recording
+// the JSON body of the last request to each path.
+type fakeServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies map[string]params
+	calls  map[string]int
+}
+
+func newFakeServer(t *testing.T, responses map[string]string) (*fakeServer, *Client) {
+	t.Helper()
+
+	f := &fakeServer{
+		bodies: map[string]params{},
+		calls:  map[string]int{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		f.calls[r.URL.Path]++
+		if r.URL.Path == "/oauth/v1/token" {
+			_, _ = io.WriteString(w, `{"access_token":"token","refresh_token":"refresh","token_type":"bearer"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" && r.URL.Path != "/oauth/v1/revoke" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"error":true,"result_number":401}`)
+			return
+		}
+		body := params{}
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		f.bodies[r.URL.Path] = body
+
+		res, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error":true,"result_number":404}`)
+			return
+		}
+		_, _ = io.WriteString(w, res)
+	}))
+	t.Cleanup(f.Close)
+
+	c := NewClient("username", "password")
+	c.SetApiEndpoint(f.URL)
+	return f, c
+}
+
+func TestClientAPIError(t *testing.T) {
+	_, c := newFakeServer(t, nil)
+
+	_, err := c.NewIbanInquiryService().Iban("IR820540102680020817909002").Do(context.Background())
+	if !IsAPIError(err) {
+		t.Fatalf("expected api error, got %v", err)
+	}
+}
+
+func TestWithMetadata(t *testing.T) {
+	_, c := newFakeServer(t, map[string]string{
+		"/sanboom/v1/banksinquiry/cardtoiban": `{"error":false,"message":{"iban":"IR820540102680020817909002"},"trace_id":"t7"}`,
+	})
+
+	var md ResponseMetadata
+	_, err := c.NewCardToIbanService().
+		CardNumber("6219861234567898").
+		Do(context.Background(), WithMetadata(&md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.StatusCode != http.StatusOK || md.Endpoint != "/sanboom/v1/banksinquiry/cardtoiban" || md.Method != http.MethodPost {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+	if md.TraceId == "" || !strings.Contains(md.URL, "traceid="+md.TraceId) {
+		t.Fatalf("trace id %q not in url %q", md.TraceId, md.URL)
+	}
+	if md.Header.Get("Content-Type") == "" || md.Latency <= 0 {
+		t.Fatalf("unexpected metadata %+v", md)
+	}
+}
+
+func TestTraceId(t *testing.T) {
+	_, c := newFakeServer(t, map[string]string{
+		"/sanboom/v1/banksinquiry/cardtoiban": `{"error":false,"message":{"iban":"IR820540102680020817909002"}}`,
+	})
+	c.TraceIdGenerator = func() string { return "generated" }
+
+	ctx := ContextWithTraceId(context.Background(), "from-context")
+	tests := []struct {
+		ctx  context.Context
+		opts []RequestOption
+		want string
+	}{
+		{context.Background(), nil, "generated"},
+		{ctx, nil, "from-context"},
+		{ctx, []RequestOption{WithTraceId("from-option")}, "from-option"},
+	}
+	for _, tt := range tests {
+		var md ResponseMetadata
+		res, err := c.NewCardToIbanService().
+			CardNumber("6219861234567898").
+			Do(tt.ctx, append(tt.opts, WithMetadata(&md))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.RequestTraceId != tt.want || !strings.HasSuffix(md.URL, "traceid="+tt.want) {
+			t.Fatalf("got trace id %q and url %q, want %q", res.RequestTraceId, md.URL, tt.want)
+		}
+	}
+
+	_, err := c.NewIbanInquiryService().
+		Iban("IR820540102680020817909002").
+		Do(ctx)
+	if !strings.Contains(err.Error(), "traceid=from-context") {
+		t.Fatalf("trace id missing from error %q", err)
+	}
+
+	errTruncated := errors.New("connection reset mid-body")
+	c.do = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(iotest.ErrReader(errTruncated)), Request: req}, nil
+	}
+	_, err = c.NewCardToIbanService().
+		CardNumber("6219861234567898").
+		Do(ctx)
+	if !errors.Is(err, errTruncated) || !strings.Contains(err.Error(), "traceid=from-context") {
+		t.Fatalf("trace id missing from read error %q", err)
+	}
+}
+
+func TestCoalescing(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointCardToIban: `{"error":false,"message":{"iban":"IR820540102680020817909002"}}`,
+	})
+	c.EnableCoalescing(EndpointCardToIban)
+	c.cache.set(CacheAccessToken, "token")
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	c.do = func(req *http.Request) (*http.Response, error) {
+		started <- struct{}{}
+		<-release
+		return http.DefaultClient.Do(req)
+	}
+
+	const n = 5
+	var wg sync.WaitGroup
+	results := make([]*CardToIban, n)
+	metadata := make([]ResponseMetadata, n)
+	call := func(i int) {
+		defer wg.Done()
+		res, err := c.NewCardToIbanService().
+			CardNumber("6219-8612-3456-7898").
+			Do(context.Background(), WithMetadata(&metadata[i]))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		results[i] = res
+	}
+	wg.Add(n)
+	go call(0)
+	<-started
+	for i := 1; i < n; i++ {
+		go call(i)
+	}
+	// give the others time to join the call in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if f.calls[EndpointCardToIban] != 1 {
+		t.Fatalf("expected one upstream call, got %d", f.calls[EndpointCardToIban])
+	}
+	coalesced := 0
+	for i, res := range results {
+		if res == nil || res.Message.Iban != "IR820540102680020817909002" || res.RequestTraceId != results[0].RequestTraceId {
+			t.Fatalf("unexpected result %d: %+v", i, res)
+		}
+		if metadata[i].Coalesced {
+			coalesced++
+		}
+	}
+	if coalesced != n-1 || len(c.flights.flights) != 0 {
+		t.Fatalf("expected %d coalesced calls, got %d", n-1, coalesced)
+	}
+}
+
+func TestCoalescingLeaderCanceledAfterResponse(t *testing.T) {
+	c := NewClient("username", "password")
+	c.EnableCoalescing(EndpointCardToIban)
+	c.cache.set(CacheAccessToken, "token")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	calls := 0
+	started := make(chan struct{})
+	release := make(chan struct{})
+	c.do = func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		calls++
+		first := calls == 1
+		mu.Unlock()
+		if first {
+			close(started)
+			<-release
+		}
+		// the response is in hand when the leader's caller gives up
+		cancel()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"error":false,"message":{"iban":"IR820540102680020817909002"}}`)),
+			Request:    req,
+		}, nil
+	}
+
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		_, _ = c.NewCardToIbanService().CardNumber("6219861234567898").Do(ctx)
+	}()
+	<-started
+
+	const n = 4
+	var wg sync.WaitGroup
+	results := make([]*CardToIban, n)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := c.NewCardToIbanService().CardNumber("6219861234567898").Do(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			results[i] = res
+		}()
+	}
+	// give the others time to join the call in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-leaderDone
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("waiters should reuse the response, got %d calls", calls)
+	}
+	for i, res := range results {
+		if res == nil || res.Message.Iban != "IR820540102680020817909002" {
+			t.Fatalf("unexpected result %d: %+v", i, res)
+		}
+	}
+}
+
+func TestCoalescingLeaderGivesUp(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointCardToIban: `{"error":false,"message":{"iban":"IR820540102680020817909002"}}`,
+	})
+	c.EnableCoalescing(EndpointCardToIban)
+	c.cache.set(CacheAccessToken, "token")
+
+	var mu sync.Mutex
+	calls := 0
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	c.do = func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		calls++
+		first := calls == 1
+		mu.Unlock()
+
+		started <- struct{}{}
+		if first {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		<-release
+		return http.DefaultClient.Do(req)
+	}
+
+	const n = 5
+	ctx, cancel := context.WithCancel(context.Background())
+	var leaderErr error
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		_, leaderErr = c.NewCardToIbanService().CardNumber("6219861234567898").Do(ctx)
+	}()
+	<-started
+
+	var wg sync.WaitGroup
+	results := make([]*CardToIban, n-1)
+	metadata := make([]ResponseMetadata, n-1)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := c.NewCardToIbanService().
+				CardNumber("6219861234567898").
+				Do(context.Background(), WithMetadata(&metadata[i]))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			results[i] = res
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-leaderDone
+	if !errors.Is(leaderErr, context.Canceled) {
+		t.Fatalf("expected the leader to be canceled, got %v", leaderErr)
+	}
+
+	// one waiter takes over; the rest wait for it
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 2 || f.calls[EndpointCardToIban] != 1 {
+		t.Fatalf("expected a single call after the leader gave up, got %d", calls-1)
+	}
+	coalesced := 0
+	for i, res := range results {
+		if res == nil || res.Message.Iban != "IR820540102680020817909002" || res.RequestTraceId != results[0].RequestTraceId {
+			t.Fatalf("unexpected result %d: %+v", i, res)
+		}
+		if metadata[i].Coalesced {
+			coalesced++
+		}
+	}
+	if coalesced != n-2 || len(c.flights.flights) != 0 {
+		t.Fatalf("expected %d coalesced calls, got %d", n-2, coalesced)
+	}
+}
+
+func TestRequestKey(t *testing.T) {
+	r := &request{method: http.MethodPost, endpoint: EndpointCardToIban, json: []byte(`{"cardNumber":"6219861234567898"}`)}
+	other := &request{method: http.MethodPost, endpoint: EndpointCardToIban, json: []byte(`{"cardNumber":"6219861234567899"}`)}
+
+	a, b := NewClient("username", "password"), NewClient("username", "password")
+	if requestKey(a.keySecret, a.Username, r) != requestKey(a.keySecret, a.Username, r) {
+		t.Fatal("keys of the same request should match")
+	}
+	if requestKey(a.keySecret, a.Username, r) == requestKey(a.keySecret, a.Username, other) {
+		t.Fatal("keys of different requests should differ")
+	}
+	if requestKey(a.keySecret, a.Username, r) == requestKey(b.keySecret, b.Username, r) {
+		t.Fatal("keys should depend on the client's secret")
+	}
+}
@@ -0,0 +1,143 @@
+package sanbod
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+)
+
+// flight is one upstream call shared by every identical request made while
+// it is running.
+type flight struct {
+	done    chan struct{}
+	data    []byte
+	err     error
+	traceId string
+	md      ResponseMetadata
+	// abandoned is set when the caller making the call gave up on it.
+	abandoned bool
+}
+
+type flightGroup struct {
+	mu        sync.Mutex
+	endpoints map[string]bool
+	flights   map[string]*flight
+}
+
+// EnableCoalescing makes identical requests to the given endpoints, such as
+// EndpointCardToIban, share a single upstream call while one is in flight.
+// Requests are identical when their method, endpoint and normalized body
+// match; every caller gets the result and traceid of the shared call.
+func (c *Client) EnableCoalescing(endpoints ...string) *Client {
+	c.flights.mu.Lock()
+	defer c.flights.mu.Unlock()
+
+	if c.flights.endpoints == nil {
+		c.flights.endpoints = make(map[string]bool)
+		c.flights.flights = make(map[string]*flight)
+	}
+	for _, endpoint := range endpoints {
+		c.flights.endpoints[endpoint] = true
+	}
+	return c
+}
+
+// newKeySecret returns the random key a client hashes its request keys
+// with.
+func newKeySecret() []byte {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		panic("sanbod: reading random key: " + err.Error())
+	}
+	return secret
+}
+
+// requestKey hashes what makes two requests of the account username
+// identical with secret, so that keys reveal nothing about the inputs and
+// cannot be worked out without it.
+func requestKey(secret []byte, username string, r *request) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(username))
+	h.Write([]byte{0})
+	h.Write([]byte(r.method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.endpoint))
+	h.Write([]byte{0})
+	h.Write([]byte(r.form.Encode()))
+	h.Write([]byte{0})
+	h.Write(r.json)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Client) coalesced(ctx context.Context, r *request) ([]byte, error) {
+	g := &c.flights
+	g.mu.Lock()
+	if !g.endpoints[r.endpoint] {
+		g.mu.Unlock()
+		return c.fetch(ctx, r)
+	}
+	g.mu.Unlock()
+
+	key := requestKey(c.keySecret, c.Username, r)
+	for {
+		g.mu.Lock()
+		f, ok := g.flights[key]
+		if !ok {
+			f = &flight{done: make(chan struct{})}
+			g.flights[key] = f
+			g.mu.Unlock()
+			return c.lead(ctx, r, key, f)
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return []byte{}, ctx.Err()
+		case <-f.done:
+		}
+		// the caller that made the call gave up; the first waiter to get
+		// here makes it again and the others wait for that one
+		if f.abandoned {
+			continue
+		}
+		r.traceId = f.traceId
+		if r.metadata != nil {
+			metadataMu.Lock()
+			*r.metadata = f.md
+			r.metadata.Coalesced = true
+			metadataMu.Unlock()
+		}
+		return f.data, f.err
+	}
+}
+
+// lead makes the call of flight f for r and hands the result to the
+// callers waiting on it.
+func (c *Client) lead(ctx context.Context, r *request, key string, f *flight) ([]byte, error) {
+	md := r.metadata
+	r.metadata = &f.md
+	f.data, f.err = c.fetch(ctx, r)
+	f.traceId = r.traceId
+	// only a call cut short by the leader's cancellation is abandoned; a
+	// response that arrived before it is still good for the waiters
+	f.abandoned = f.err != nil && errors.Is(f.err, ctx.Err())
+	r.metadata = md
+	if md != nil {
+		metadataMu.Lock()
+		*md = f.md
+		metadataMu.Unlock()
+	}
+
+	g := &c.flights
+	g.mu.Lock()
+	delete(g.flights, key)
+	g.mu.Unlock()
+	close(f.done)
+
+	return f.data, f.err
+}
@@ -2,29 +2,47 @@ package sanbod
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 )
 
 type CardToAccountNumberService struct {
 	c          *Client
-	cardNumber string
+	cardNumber CardNumber
 }
 
-func (j *CardToAccountNumberService) CardNumber(cardNumber string) *CardToAccountNumberService {
+func (j *CardToAccountNumberService) CardNumber(cardNumber CardNumber) *CardToAccountNumberService {
 	j.cardNumber = cardNumber
 
 	return j
 }
 
+func (j *CardToAccountNumberService) validated(raw bool) (in CardToAccountNumberService, err error) {
+	v := &validator{raw: raw}
+	in = *j
+	in.cardNumber = parseField(v, "cardNumber", j.cardNumber, true, ParseCardNumber)
+	return in, v.err()
+}
+
+func (j *CardToAccountNumberService) Validate() error {
+	_, err := j.validated(false)
+	return err
+}
+
 func (j *CardToAccountNumberService) Do(ctx context.Context, opts ...RequestOption) (res *CardToAccountNumber, err error) {
+	in, err := j.validated(!j.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/sanboom/v1/banksinquiry/cardtodeposit",
+		endpoint: EndpointCardToDeposit,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"cardNumber": j.cardNumber,
+		"cardNumber": string(in.cardNumber),
 	})
 	data, err := j.c.callAPI(ctx, r, opts...)
 	if err != nil {
@@ -36,39 +54,54 @@ func (j *CardToAccountNumberService) Do(ctx context.Context, opts ...RequestOpti
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type CardToAccountNumber struct {
-	Error   bool `json:"error"`
-	Message struct {
-		CardNumber    string `json:"cardNumber"`
-		DepositNumber string `json:"depositNumber"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
+type CardDeposit struct {
+	CardNumber    string `json:"cardNumber"`
+	DepositNumber string `json:"depositNumber"`
 }
 
+type CardToAccountNumber = Response[CardDeposit]
+
 type CardToIbanService struct {
 	c          *Client
-	cardNumber string
+	cardNumber CardNumber
 }
 
-func (j *CardToIbanService) CardNumber(cardNumber string) *CardToIbanService {
+func (j *CardToIbanService) CardNumber(cardNumber CardNumber) *CardToIbanService {
 	j.cardNumber = cardNumber
 	return j
 }
 
+func (j *CardToIbanService) validated(raw bool) (in CardToIbanService, err error) {
+	v := &validator{raw: raw}
+	in = *j
+	in.cardNumber = parseField(v, "cardNumber", j.cardNumber, true, ParseCardNumber)
+	return in, v.err()
+}
+
+func (j *CardToIbanService) Validate() error {
+	_, err := j.validated(false)
+	return err
+}
+
 func (j *CardToIbanService) Do(ctx context.Context, opts ...RequestOption) (res *CardToIban, err error) {
+	in, err := j.validated(!j.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/sanboom/v1/banksinquiry/cardtoiban",
+		endpoint: EndpointCardToIban,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"cardNumber": j.cardNumber,
+		"cardNumber": string(in.cardNumber),
 	})
 
 	data, err := j.c.callAPI(ctx, r, opts...)
@@ -81,48 +114,68 @@ func (j *CardToIbanService) Do(ctx context.Context, opts ...RequestOption) (res
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type CardToIban struct {
-	Error   bool `json:"error"`
-	Message struct {
-		CardNumber string `json:"cardNumber"`
-		Iban       string `json:"iban"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
+type CardIban struct {
+	CardNumber string `json:"cardNumber"`
+	Iban       string `json:"iban"`
 }
 
+type CardToIban = Response[CardIban]
+
 type AccountNumberToIbanService struct {
 	c             *Client
-	provider      string
-	depositNumber string
+	provider      Bank
+	depositNumber DepositNumber
 }
 
-func (j *AccountNumberToIbanService) Provider(provider string) *AccountNumberToIbanService {
+func (j *AccountNumberToIbanService) Provider(provider Bank) *AccountNumberToIbanService {
 	j.provider = provider
 
 	return j
 }
 
-func (j *AccountNumberToIbanService) DepositNumber(depositNumber string) *AccountNumberToIbanService {
+func (j *AccountNumberToIbanService) DepositNumber(depositNumber DepositNumber) *AccountNumberToIbanService {
 	j.depositNumber = depositNumber
 
 	return j
 }
 
+func (j *AccountNumberToIbanService) validated(raw bool) (in AccountNumberToIbanService, err error) {
+	v := &validator{raw: raw}
+	in = *j
+	if j.provider == "" {
+		v.missing("provider")
+	} else if !j.provider.IsKnown() {
+		v.invalid("provider", fmt.Errorf("%w: %q", ErrUnknownBank, j.provider))
+	}
+	in.depositNumber = parseField(v, "depositNumber", j.depositNumber, true, ParseDepositNumber)
+	return in, v.err()
+}
+
+func (j *AccountNumberToIbanService) Validate() error {
+	_, err := j.validated(false)
+	return err
+}
+
 func (j *AccountNumberToIbanService) Do(ctx context.Context, opts ...RequestOption) (res *AccountNumberToIban, err error) {
+	in, err := j.validated(!j.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/sanboom/v1/banksinquiry/deposittoiban",
+		endpoint: EndpointDepositToIban,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"provider":      j.provider,
-		"depositNumber": j.depositNumber,
+		"provider":      string(in.provider),
+		"depositNumber": string(in.depositNumber),
 	})
 
 	data, err := j.c.callAPI(ctx, r, opts...)
@@ -135,40 +188,55 @@ func (j *AccountNumberToIbanService) Do(ctx context.Context, opts ...RequestOpti
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type AccountNumberToIban struct {
-	Error   bool `json:"error"`
-	Message struct {
-		DepositNumber string `json:"depositNumber"`
-		Iban          string `json:"iban"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
+type DepositIban struct {
+	DepositNumber string `json:"depositNumber"`
+	Iban          string `json:"iban"`
 }
 
+type AccountNumberToIban = Response[DepositIban]
+
 type IbanToAccountNumberService struct {
 	c    *Client
-	iban string
+	iban IBAN
 }
 
-func (j *IbanToAccountNumberService) Iban(iban string) *IbanToAccountNumberService {
+func (j *IbanToAccountNumberService) Iban(iban IBAN) *IbanToAccountNumberService {
 	j.iban = iban
 
 	return j
 }
 
+func (j *IbanToAccountNumberService) validated(raw bool) (in IbanToAccountNumberService, err error) {
+	v := &validator{raw: raw}
+	in = *j
+	in.iban = parseField(v, "iban", j.iban, true, ParseIBAN)
+	return in, v.err()
+}
+
+func (j *IbanToAccountNumberService) Validate() error {
+	_, err := j.validated(false)
+	return err
+}
+
 func (j *IbanToAccountNumberService) Do(ctx context.Context, opts ...RequestOption) (res *IbanToAccountNumber, err error) {
+	in, err := j.validated(!j.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/banks/v1/ibantodeposit",
+		endpoint: EndpointIbanToDeposit,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"iban": j.iban,
+		"iban": string(in.iban),
 	})
 
 	data, err := j.c.callAPI(ctx, r, opts...)
@@ -181,16 +249,9 @@ func (j *IbanToAccountNumberService) Do(ctx context.Context, opts ...RequestOpti
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type IbanToAccountNumber struct {
-	Error   bool `json:"error"`
-	Message struct {
-		DepositNumber string `json:"depositNumber"`
-		Iban          string `json:"iban"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
-}
+type IbanToAccountNumber = Response[DepositIban]
@@ -0,0 +1,110 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCardToAccountNumberService(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		"/sanboom/v1/banksinquiry/cardtodeposit": `{"error":false,"message":{"cardNumber":"6104331234567890","depositNumber":"1234567890"},"result_number":1,"trace_id":"t1"}`,
+	})
+
+	res, err := c.NewCardToAccountNumberService().
+		CardNumber("6104-3312 3456 7890").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message.DepositNumber != "1234567890" || res.TraceId != "t1" {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	if got := f.bodies["/sanboom/v1/banksinquiry/cardtodeposit"]["cardNumber"]; got != "6104331234567890" {
+		t.Fatalf("card number sent as %v", got)
+	}
+}
+
+func TestCardToIbanService(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		"/sanboom/v1/banksinquiry/cardtoiban": `{"error":false,"message":{"cardNumber":"6219861234567898","iban":"IR820540102680020817909002"},"result_number":1,"trace_id":"t2"}`,
+	})
+
+	res, err := c.NewCardToIbanService().
+		CardNumber("۶۲۱۹۸۶۱۲۳۴۵۶۷۸۹۸").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message.Iban != "IR820540102680020817909002" {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	if got := f.bodies["/sanboom/v1/banksinquiry/cardtoiban"]["cardNumber"]; got != "6219861234567898" {
+		t.Fatalf("card number sent as %v", got)
+	}
+}
+
+func TestCardToIbanServiceInvalidCard(t *testing.T) {
+	f, c := newFakeServer(t, nil)
+
+	_, err := c.NewCardToIbanService().CardNumber("6219861234567890").Do(context.Background())
+	if !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+	if len(f.calls) != 0 {
+		t.Fatalf("expected no http calls, got %v", f.calls)
+	}
+}
+
+func TestAccountNumberToIbanService(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		"/sanboom/v1/banksinquiry/deposittoiban": `{"error":false,"message":{"depositNumber":"0102680020817909002","iban":"IR820540102680020817909002"},"result_number":1,"trace_id":"t3"}`,
+	})
+
+	res, err := c.NewAccountNumberToIbanService().
+		Provider(ParsianBank).
+		DepositNumber("0102680020817909002").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message.Iban != "IR820540102680020817909002" {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	body := f.bodies["/sanboom/v1/banksinquiry/deposittoiban"]
+	if body["provider"] != "PARSIAN" || body["depositNumber"] != "0102680020817909002" {
+		t.Fatalf("unexpected request body %v", body)
+	}
+}
+
+func TestAccountNumberToIbanServiceValidation(t *testing.T) {
+	_, c := newFakeServer(t, nil)
+
+	_, err := c.NewAccountNumberToIbanService().Provider("NOPE").Do(context.Background())
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 2 {
+		t.Fatalf("expected two field errors, got %v", err)
+	}
+	if verr.Fields[0].Field != "provider" || verr.Fields[1].Reason != ReasonMissing {
+		t.Fatalf("unexpected field errors %+v", verr.Fields)
+	}
+}
+
+func TestIbanToAccountNumberService(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		"/banks/v1/ibantodeposit": `{"error":false,"message":{"depositNumber":"0102680020817909002","iban":"IR820540102680020817909002"},"result_number":1,"trace_id":"t4"}`,
+	})
+
+	res, err := c.NewIbanToAccountNumberService().
+		Iban("820540102680020817909002").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message.DepositNumber != "0102680020817909002" {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	if got := f.bodies["/banks/v1/ibantodeposit"]["iban"]; got != "IR820540102680020817909002" {
+		t.Fatalf("iban sent as %v", got)
+	}
+}
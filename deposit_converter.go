@@ -0,0 +1,162 @@
+package sanbod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DepositScheme maps a bank's deposit numbers to the nineteen digit account
+// part of its IBANs and back.
+type DepositScheme interface {
+	AccountPart(depositNumber DepositNumber) (string, error)
+	DepositNumber(accountPart string) (DepositNumber, error)
+}
+
+// ZeroPaddedScheme covers banks whose IBAN account part is the deposit
+// number left padded with zeros, for deposit numbers of up to Width digits.
+type ZeroPaddedScheme struct {
+	Width int
+}
+
+func (z ZeroPaddedScheme) AccountPart(depositNumber DepositNumber) (string, error) {
+	digits := string(depositNumber)
+	if !isDigits(digits) || len(digits) > z.Width {
+		return "", fmt.Errorf("%w: deposit number must be at most %d digits", ErrUnsupportedDeposit, z.Width)
+	}
+	return strings.Repeat("0", 19-len(digits)) + digits, nil
+}
+
+func (z ZeroPaddedScheme) DepositNumber(accountPart string) (DepositNumber, error) {
+	if len(accountPart) != 19 || strings.TrimLeft(accountPart[:19-z.Width], "0") != "" {
+		return "", fmt.Errorf("%w: account part is not a padded %d digit deposit number", ErrUnsupportedDeposit, z.Width)
+	}
+	return DepositNumber(accountPart[19-z.Width:]), nil
+}
+
+var (
+	depositSchemesMu sync.RWMutex
+	depositSchemes   = map[Bank]DepositScheme{}
+)
+
+// RegisterDepositScheme adds or replaces the offline scheme used for bank.
+// No scheme is registered by default: a wrong one yields valid looking IBANs
+// of someone else's account, so register only schemes checked against the
+// bank's published format.
+func RegisterDepositScheme(bank Bank, scheme DepositScheme) {
+	depositSchemesMu.Lock()
+	defer depositSchemesMu.Unlock()
+
+	depositSchemes[bank] = scheme
+}
+
+func depositScheme(bank Bank) (DepositScheme, bool) {
+	depositSchemesMu.RLock()
+	defer depositSchemesMu.RUnlock()
+
+	scheme, ok := depositSchemes[bank]
+	return scheme, ok
+}
+
+// DepositConverter converts between deposit numbers and IBANs locally when
+// a scheme is registered for the bank and falls back to the API otherwise. In strict
+// mode every offline result is confirmed against the API as well.
+type DepositConverter struct {
+	c      *Client
+	strict bool
+}
+
+func (c *Client) NewDepositConverter() *DepositConverter {
+	return &DepositConverter{c: c}
+}
+
+func (d *DepositConverter) Strict(strict bool) *DepositConverter {
+	d.strict = strict
+	return d
+}
+
+// Supports reports whether bank can be converted without calling the API.
+func (d *DepositConverter) Supports(bank Bank) bool {
+	_, ok := depositScheme(bank)
+	return ok
+}
+
+func (d *DepositConverter) DepositToIban(ctx context.Context, bank Bank, depositNumber DepositNumber, opts ...RequestOption) (res *AccountNumberToIban, err error) {
+	depositNumber, err = ParseDepositNumber(string(depositNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	iban, offlineErr := offlineDepositToIban(bank, depositNumber)
+	if offlineErr == nil && !d.strict {
+		res = new(AccountNumberToIban)
+		res.Message.DepositNumber = depositNumber.Digits()
+		res.Message.Iban = string(iban)
+		return res, nil
+	}
+
+	res, err = d.c.NewAccountNumberToIbanService().
+		Provider(bank).
+		DepositNumber(depositNumber).
+		Do(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if offlineErr == nil {
+		remote, err := ParseIBAN(res.Message.Iban)
+		if err != nil || remote != iban {
+			return nil, fmt.Errorf("%w: derived %s, api returned %s", ErrOfflineMismatch, iban, res.Message.Iban)
+		}
+	}
+	return res, nil
+}
+
+func (d *DepositConverter) IbanToDeposit(ctx context.Context, iban IBAN, opts ...RequestOption) (res *IbanToAccountNumber, err error) {
+	iban, err = ParseIBAN(string(iban))
+	if err != nil {
+		return nil, err
+	}
+
+	depositNumber, offlineErr := offlineIbanToDeposit(iban)
+	if offlineErr == nil && !d.strict {
+		res = new(IbanToAccountNumber)
+		res.Message.DepositNumber = string(depositNumber)
+		res.Message.Iban = string(iban)
+		return res, nil
+	}
+
+	res, err = d.c.NewIbanToAccountNumberService().
+		Iban(iban).
+		Do(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if offlineErr == nil && Normalize(res.Message.DepositNumber) != string(depositNumber) {
+		return nil, fmt.Errorf("%w: derived %s, api returned %s", ErrOfflineMismatch, depositNumber, res.Message.DepositNumber)
+	}
+	return res, nil
+}
+
+func offlineDepositToIban(bank Bank, depositNumber DepositNumber) (IBAN, error) {
+	scheme, ok := depositScheme(bank)
+	if !ok {
+		return "", fmt.Errorf("%w: no offline scheme for %s", ErrUnsupportedDeposit, bank)
+	}
+	// schemes see the bare digits, whatever separators the input used
+	accountPart, err := scheme.AccountPart(DepositNumber(depositNumber.Digits()))
+	if err != nil {
+		return "", err
+	}
+	return NewIBAN(bank.IbanCode(), accountPart)
+}
+
+func offlineIbanToDeposit(iban IBAN) (DepositNumber, error) {
+	scheme, ok := depositScheme(iban.Bank())
+	if !ok {
+		return "", fmt.Errorf("%w: no offline scheme for %s", ErrUnsupportedDeposit, iban.Bank())
+	}
+	return scheme.DepositNumber(iban.AccountPart())
+}
@@ -0,0 +1,158 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// registerTestScheme registers scheme for bank for the duration of the test.
+func registerTestScheme(t *testing.T, bank Bank, scheme DepositScheme) {
+	t.Helper()
+
+	RegisterDepositScheme(bank, scheme)
+	t.Cleanup(func() {
+		depositSchemesMu.Lock()
+		delete(depositSchemes, bank)
+		depositSchemesMu.Unlock()
+	})
+}
+
+func TestDepositConverterNoDefaultSchemes(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointDepositToIban: `{"error":false,"message":{"depositNumber":"1234567890","iban":"IR160120000000001234567890"}}`,
+	})
+	conv := c.NewDepositConverter()
+	for bank := range banks {
+		if conv.Supports(bank) {
+			t.Errorf("%s should need the api until a scheme is registered", bank)
+		}
+	}
+
+	_, err := conv.DepositToIban(context.Background(), BankMellat, "1234567890")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.calls[EndpointDepositToIban] != 1 {
+		t.Fatalf("expected the api to be called, got %d calls", f.calls[EndpointDepositToIban])
+	}
+}
+
+func TestDepositConverterDepositToIban(t *testing.T) {
+	registerTestScheme(t, BankMellat, ZeroPaddedScheme{Width: 10})
+	registerTestScheme(t, TejaratBank, ZeroPaddedScheme{Width: 10})
+
+	tests := []struct {
+		name          string
+		bank          Bank
+		depositNumber DepositNumber
+		wantIban      string
+		wantDeposit   string
+		wantCalls     int
+	}{
+		{"plain", BankMellat, "1234567890", "IR160120000000001234567890", "1234567890", 0},
+		{"dashes", BankMellat, "1234-567-890", "IR160120000000001234567890", "1234567890", 0},
+		{"dots and slashes", BankMellat, "1234.567/890", "IR160120000000001234567890", "1234567890", 0},
+		{"persian digits", TejaratBank, "۹۸۷۶-۵۴۳-۲۱۰", "IR800180000000009876543210", "9876543210", 0},
+		{"too long for the scheme", BankMellat, "12345-678901", "IR820540102680020817909002", "0102680020817909002", 1},
+		{"unsupported bank", SamanBank, "849-40-1234567-1", "IR820540102680020817909002", "0102680020817909002", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, c := newFakeServer(t, map[string]string{
+				EndpointDepositToIban: `{"error":false,"message":{"depositNumber":"0102680020817909002","iban":"IR820540102680020817909002"}}`,
+			})
+
+			res, err := c.NewDepositConverter().DepositToIban(context.Background(), tt.bank, tt.depositNumber)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Message.Iban != tt.wantIban || res.Message.DepositNumber != tt.wantDeposit {
+				t.Fatalf("got %+v", res.Message)
+			}
+			if f.calls[EndpointDepositToIban] != tt.wantCalls {
+				t.Fatalf("expected %d api calls, got %d", tt.wantCalls, f.calls[EndpointDepositToIban])
+			}
+		})
+	}
+}
+
+func TestDepositConverterStrict(t *testing.T) {
+	registerTestScheme(t, BankMellat, ZeroPaddedScheme{Width: 10})
+	f, c := newFakeServer(t, map[string]string{
+		EndpointDepositToIban: `{"error":false,"message":{"depositNumber":"1234567890","iban":"IR160120000000001234567890"}}`,
+	})
+	conv := c.NewDepositConverter().Strict(true)
+
+	_, err := conv.DepositToIban(context.Background(), BankMellat, "1234-567-890")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.calls[EndpointDepositToIban] != 1 {
+		t.Fatalf("strict mode should confirm with the api, got %d calls", f.calls[EndpointDepositToIban])
+	}
+
+	_, err = conv.DepositToIban(context.Background(), BankMellat, "1234-567-891")
+	if !errors.Is(err, ErrOfflineMismatch) {
+		t.Fatalf("expected ErrOfflineMismatch, got %v", err)
+	}
+}
+
+func TestDepositConverterIbanToDeposit(t *testing.T) {
+	registerTestScheme(t, BankMellat, ZeroPaddedScheme{Width: 10})
+	f, c := newFakeServer(t, nil)
+
+	res, err := c.NewDepositConverter().IbanToDeposit(context.Background(), "IR16 0120 0000 0000 1234 5678 90")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message.DepositNumber != "1234567890" || res.Message.Iban != "IR160120000000001234567890" || len(f.calls) != 0 {
+		t.Fatalf("unexpected offline result %+v after %v", res.Message, f.calls)
+	}
+}
+
+func TestZeroPaddedScheme(t *testing.T) {
+	z := ZeroPaddedScheme{Width: 10}
+	for in, want := range map[DepositNumber]string{
+		"1234567890": "0000000001234567890",
+		"12345":      "0000000000000012345",
+	} {
+		got, err := z.AccountPart(in)
+		if err != nil || got != want {
+			t.Errorf("AccountPart(%q) = %q, %v, want %q", in, got, err, want)
+		}
+		back, err := z.DepositNumber(got)
+		if err != nil || back != DepositNumber(want[19-10:]) {
+			t.Errorf("DepositNumber(%q) = %q, %v", got, back, err)
+		}
+	}
+	for _, in := range []DepositNumber{"12345678901", "1234-5678", ""} {
+		if _, err := z.AccountPart(in); !errors.Is(err, ErrUnsupportedDeposit) {
+			t.Errorf("AccountPart(%q) error = %v, want ErrUnsupportedDeposit", in, err)
+		}
+	}
+	for _, in := range []string{"0000000101234567890", "000000001234567890", ""} {
+		if _, err := z.DepositNumber(in); !errors.Is(err, ErrUnsupportedDeposit) {
+			t.Errorf("DepositNumber(%q) error = %v, want ErrUnsupportedDeposit", in, err)
+		}
+	}
+}
+
+func TestRegisterDepositScheme(t *testing.T) {
+	_, c := newFakeServer(t, nil)
+	conv := c.NewDepositConverter()
+	if conv.Supports(PasargadBank) {
+		t.Fatal("pasargad should need the api by default")
+	}
+
+	registerTestScheme(t, PasargadBank, ZeroPaddedScheme{Width: 12})
+
+	res, err := conv.DepositToIban(context.Background(), PasargadBank, "123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	iban := IBAN(res.Message.Iban)
+	if iban.Validate() != nil || iban.Bank() != PasargadBank || iban.AccountPart() != "0000000123456789012" {
+		t.Fatalf("unexpected iban %s", iban)
+	}
+}
@@ -0,0 +1,49 @@
+package sanbod
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DepositNumber is a bank account number as printed by the bank, digits
+// optionally grouped with dots, dashes or slashes.
+type DepositNumber string
+
+func ParseDepositNumber(s string) (DepositNumber, error) {
+	s = strings.ReplaceAll(Normalize(s), " ", "")
+
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.' || r == '-' || r == '/':
+		default:
+			return "", fmt.Errorf("%w: unexpected character %q", ErrInvalidDepositNumber, r)
+		}
+	}
+	if digits < 5 || digits > 19 {
+		return "", fmt.Errorf("%w: must have 5 to 19 digits", ErrInvalidDepositNumber)
+	}
+
+	return DepositNumber(s), nil
+}
+
+func (d DepositNumber) Validate() error {
+	_, err := ParseDepositNumber(string(d))
+	return err
+}
+
+// Digits returns the deposit number without separators.
+func (d DepositNumber) Digits() string {
+	return strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+		return r
+	}, string(d))
+}
+
+func (d DepositNumber) String() string {
+	return string(d)
+}
@@ -0,0 +1,180 @@
+package sanbod
+
+import (
+	"strings"
+)
+
+type Gender string
+
+const (
+	GenderMale   Gender = "MALE"
+	GenderFemale Gender = "FEMALE"
+)
+
+var genderValues = map[string]Gender{
+	"MALE":   GenderMale,
+	"M":      GenderMale,
+	"1":      GenderMale,
+	"مرد":    GenderMale,
+	"FEMALE": GenderFemale,
+	"F":      GenderFemale,
+	"2":      GenderFemale,
+	"زن":     GenderFemale,
+}
+
+func (g Gender) IsKnown() bool {
+	return g == GenderMale || g == GenderFemale
+}
+
+func (g Gender) String() string {
+	return string(g)
+}
+
+// UnmarshalJSON maps the spellings seen from the registry to GenderMale or
+// GenderFemale and keeps any other value as received.
+func (g *Gender) UnmarshalJSON(data []byte) error {
+	raw := enumText(data)
+	if v, ok := genderValues[enumKey(raw)]; ok {
+		*g = v
+		return nil
+	}
+	*g = Gender(raw)
+	return nil
+}
+
+type DeathStatus string
+
+const (
+	DeathStatusAlive DeathStatus = "ALIVE"
+	DeathStatusDead  DeathStatus = "DEAD"
+)
+
+var deathStatusValues = map[string]DeathStatus{
+	"ALIVE":       DeathStatusAlive,
+	"LIVE":        DeathStatusAlive,
+	"0":           DeathStatusAlive,
+	"FALSE":       DeathStatusAlive,
+	"زنده":        DeathStatusAlive,
+	"در قید حیات": DeathStatusAlive,
+	"DEAD":        DeathStatusDead,
+	"DECEASED":    DeathStatusDead,
+	"1":           DeathStatusDead,
+	"TRUE":        DeathStatusDead,
+	"فوت":         DeathStatusDead,
+	"فوت شده":     DeathStatusDead,
+	"متوفی":       DeathStatusDead,
+}
+
+func (d DeathStatus) IsKnown() bool {
+	return d == DeathStatusAlive || d == DeathStatusDead
+}
+
+// IsAlive is true only when the registry positively reports the person alive.
+func (d DeathStatus) IsAlive() bool {
+	return d == DeathStatusAlive
+}
+
+func (d DeathStatus) IsDead() bool {
+	return d == DeathStatusDead
+}
+
+func (d DeathStatus) String() string {
+	return string(d)
+}
+
+func (d *DeathStatus) UnmarshalJSON(data []byte) error {
+	raw := enumText(data)
+	if v, ok := deathStatusValues[enumKey(raw)]; ok {
+		*d = v
+		return nil
+	}
+	*d = DeathStatus(raw)
+	return nil
+}
+
+// DepositStatus is the account state code returned by the IBAN inquiry.
+type DepositStatus string
+
+const (
+	DepositStatusActive            DepositStatus = "02"
+	DepositStatusBlockedWithCredit DepositStatus = "03"
+	DepositStatusBlocked           DepositStatus = "04"
+	DepositStatusDormant           DepositStatus = "05"
+	DepositStatusError             DepositStatus = "06"
+	DepositStatusOther             DepositStatus = "07"
+)
+
+var depositStatusDescriptions = map[DepositStatus]string{
+	DepositStatusActive:            "حساب فعال است",
+	DepositStatusBlockedWithCredit: "حساب مسدود با قابلیت واریز",
+	DepositStatusBlocked:           "حساب مسدود بدون قابلیت واریز",
+	DepositStatusDormant:           "حساب راکد است",
+	DepositStatusError:             "بروز خطا در پاسخ دهی",
+	DepositStatusOther:             "سایر موارد",
+}
+
+var depositStatusValues = map[string]DepositStatus{
+	"2":       DepositStatusActive,
+	"ACTIVE":  DepositStatusActive,
+	"3":       DepositStatusBlockedWithCredit,
+	"4":       DepositStatusBlocked,
+	"BLOCKED": DepositStatusBlocked,
+	"5":       DepositStatusDormant,
+	"DORMANT": DepositStatusDormant,
+	"6":       DepositStatusError,
+	"7":       DepositStatusOther,
+}
+
+func (d DepositStatus) IsKnown() bool {
+	_, ok := depositStatusDescriptions[d]
+	return ok
+}
+
+func (d DepositStatus) IsActive() bool {
+	return d == DepositStatusActive
+}
+
+// CanReceive reports whether money can still be paid into the account.
+func (d DepositStatus) CanReceive() bool {
+	return d == DepositStatusActive || d == DepositStatusBlockedWithCredit
+}
+
+func (d DepositStatus) Description() string {
+	return depositStatusDescriptions[d]
+}
+
+func (d DepositStatus) String() string {
+	return string(d)
+}
+
+func (d *DepositStatus) UnmarshalJSON(data []byte) error {
+	raw := enumText(data)
+	if v := DepositStatus(enumKey(raw)); v.IsKnown() {
+		*d = v
+		return nil
+	}
+	if v, ok := depositStatusValues[enumKey(raw)]; ok {
+		*d = v
+		return nil
+	}
+	*d = DepositStatus(raw)
+	return nil
+}
+
+// enumText returns the value of a JSON string, number or bool token as text.
+func enumText(data []byte) string {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s
+	}
+	if string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
+
+func enumKey(s string) string {
+	s = strings.NewReplacer("ي", "ی", "ك", "ک", "\u200c", " ").Replace(s)
+	s = strings.ToUpper(Normalize(s))
+	return strings.Join(strings.Fields(s), " ")
+}
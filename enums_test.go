@@ -0,0 +1,85 @@
+package sanbod
+
+import (
+	"testing"
+)
+
+func TestGenderJSON(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  Gender
+		known bool
+	}{
+		{`"MALE"`, GenderMale, true},
+		{`"male"`, GenderMale, true},
+		{`"M"`, GenderMale, true},
+		{`1`, GenderMale, true},
+		{`"مرد"`, GenderMale, true},
+		{`"FEMALE"`, GenderFemale, true},
+		{`2`, GenderFemale, true},
+		{`" زن "`, GenderFemale, true},
+		{`"X"`, Gender("X"), false},
+		{`null`, Gender(""), false},
+	}
+	for _, tt := range tests {
+		var got Gender
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil || got != tt.want || got.IsKnown() != tt.known {
+			t.Errorf("Gender from %s = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDeathStatusJSON(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  DeathStatus
+		alive bool
+		dead  bool
+	}{
+		{`"ALIVE"`, DeathStatusAlive, true, false},
+		{`0`, DeathStatusAlive, true, false},
+		{`false`, DeathStatusAlive, true, false},
+		{`"در قید حیات"`, DeathStatusAlive, true, false},
+		{`"DEAD"`, DeathStatusDead, false, true},
+		{`true`, DeathStatusDead, false, true},
+		{`"فوت شده"`, DeathStatusDead, false, true},
+		{`"متوفي"`, DeathStatusDead, false, true},
+		{`"UNKNOWN"`, DeathStatus("UNKNOWN"), false, false},
+		{`""`, DeathStatus(""), false, false},
+	}
+	for _, tt := range tests {
+		var got DeathStatus
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil || got != tt.want || got.IsAlive() != tt.alive || got.IsDead() != tt.dead {
+			t.Errorf("DeathStatus from %s = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDepositStatusJSON(t *testing.T) {
+	tests := []struct {
+		in         string
+		want       DepositStatus
+		active     bool
+		canReceive bool
+	}{
+		{`"02"`, DepositStatusActive, true, true},
+		{`2`, DepositStatusActive, true, true},
+		{`"active"`, DepositStatusActive, true, true},
+		{`"03"`, DepositStatusBlockedWithCredit, false, true},
+		{`"04"`, DepositStatusBlocked, false, false},
+		{`"BLOCKED"`, DepositStatusBlocked, false, false},
+		{`5`, DepositStatusDormant, false, false},
+		{`"06"`, DepositStatusError, false, false},
+		{`"07"`, DepositStatusOther, false, false},
+		{`"99"`, DepositStatus("99"), false, false},
+	}
+	for _, tt := range tests {
+		var got DepositStatus
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil || got != tt.want || got.IsActive() != tt.active || got.CanReceive() != tt.canReceive {
+			t.Errorf("DepositStatus from %s = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+		if got.IsKnown() != (got.Description() != "") {
+			t.Errorf("DepositStatus %q: IsKnown and Description disagree", got)
+		}
+	}
+}
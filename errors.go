@@ -1,12 +1,32 @@
 package sanbod
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+)
+
+var (
+	ErrMissingField           = errors.New("sanbod: missing field")
+	ErrInvalidChecksum        = errors.New("sanbod: invalid checksum")
+	ErrInvalidNationalCode    = errors.New("sanbod: invalid national code")
+	ErrInvalidCardNumber      = errors.New("sanbod: invalid card number")
+	ErrInvalidIBAN            = errors.New("sanbod: invalid iban")
+	ErrInvalidMobileNumber    = errors.New("sanbod: invalid mobile number")
+	ErrInvalidJalaliDate      = errors.New("sanbod: invalid jalali date")
+	ErrInvalidDepositNumber   = errors.New("sanbod: invalid deposit number")
+	ErrUnknownBank            = errors.New("sanbod: unknown bank")
+	ErrUnsupportedDeposit     = errors.New("sanbod: deposit number cannot be converted offline")
+	ErrOfflineMismatch        = errors.New("sanbod: offline conversion does not match the api")
+	ErrNoImage                = errors.New("sanbod: image is empty")
+	ErrImageTooLarge          = errors.New("sanbod: image too large")
+	ErrUnsupportedImageFormat = errors.New("sanbod: unsupported image format")
 )
 
 func (e APIError) Error() string {
-	return fmt.Sprintf("<APIError> code=%d, msg=%s", e.ResultNumber, e.Message)
+	return fmt.Sprintf("<APIError> code=%d, msg=%s, traceid=%s", e.ResultNumber, e.Message, e.RequestTraceId)
 }
 
 func IsAPIError(e error) bool {
@@ -15,10 +35,34 @@ func IsAPIError(e error) bool {
 	return ok
 }
 
+// IsRetryable reports whether a failed call may succeed later: a transport
+// error, a timeout, a 429 or 5xx from the api, or an error envelope on a 200,
+// which the api answers with when a provider behind it fails. Anything else,
+// including validation errors, other api errors, undecodable responses and
+// cancellation, is final.
+func IsRetryable(e error) bool {
+	if e == nil || errors.Is(e, context.Canceled) {
+		return false
+	}
+	var aPIError *APIError
+	if errors.As(e, &aPIError) {
+		return aPIError.StatusCode == http.StatusTooManyRequests || aPIError.StatusCode >= http.StatusInternalServerError ||
+			aPIError.StatusCode == http.StatusOK && aPIError.Err
+	}
+	if errors.Is(e, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(e, &netErr)
+}
+
 type APIError struct {
 	Err     bool `json:"error"`
 	Message struct {
 		Scope []string `json:"scope"`
 	} `json:"message"`
-	ResultNumber int64 `json:"result_number"`
+	ResultNumber   int64  `json:"result_number"`
+	TraceId        string `json:"trace_id"`
+	StatusCode     int    `json:"-"`
+	RequestTraceId string `json:"-"`
 }
@@ -0,0 +1,73 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+)
+
+func TestIsRetryable(t *testing.T) {
+	syntaxErr := json.Unmarshal([]byte(`{"error":`), new(IbanInquiry))
+	if syntaxErr == nil {
+		t.Fatal("expected a decode error")
+	}
+
+	transport := &url.Error{Op: "Post", URL: "https://sanbod.example", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"transport", transport, true},
+		{"wrapped transport", fmt.Errorf("inquiry: %w", transport), true},
+		{"dns", &net.DNSError{Err: "no such host", Name: "sanbod.example"}, true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"deadline in url error", &url.Error{Op: "Post", URL: "https://sanbod.example", Err: context.DeadlineExceeded}, true},
+		{"canceled", context.Canceled, false},
+		{"canceled in url error", &url.Error{Op: "Post", URL: "https://sanbod.example", Err: context.Canceled}, false},
+		{"429", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"500", &APIError{StatusCode: http.StatusInternalServerError}, true},
+		{"503", &APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"400", &APIError{StatusCode: http.StatusBadRequest}, false},
+		{"401", &APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"404", &APIError{StatusCode: http.StatusNotFound}, false},
+		{"error envelope", (&Response[IbanInquiry]{Error: true}).Err(), true},
+		{"validation", &ValidationError{Fields: []FieldError{{Field: "iban", Reason: ReasonChecksum}}}, false},
+		{"decode", syntaxErr, false},
+		{"eof", io.EOF, false},
+		{"other", errors.New("not mocked"), false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%s: %v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestIsRetryableFromClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `not json`)
+	}))
+	c := NewClient("username", "password")
+	c.SetApiEndpoint(srv.URL)
+	c.cache.set(CacheAccessToken, "token")
+
+	_, err := c.NewIbanInquiryService().Iban("IR820540102680020817909002").Do(context.Background())
+	if err == nil || IsRetryable(err) {
+		t.Fatalf("an undecodable response should be final, got %v", err)
+	}
+
+	srv.Close()
+	_, err = c.NewIbanInquiryService().Iban("IR820540102680020817909002").Do(context.Background())
+	if !IsRetryable(err) {
+		t.Fatalf("a refused connection should be retryable, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package sanbod
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+type IBAN string
+
+func ParseIBAN(s string) (IBAN, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(Normalize(s)))
+	s = strings.TrimPrefix(s, "IR")
+
+	if len(s) != 24 || !isDigits(s) {
+		return "", fmt.Errorf("%w: must be IR followed by 24 digits", ErrInvalidIBAN)
+	}
+
+	// ISO 13616: move the country code and check digits to the end,
+	// replace letters with numbers (I=18, R=27) and the result mod 97 must be 1.
+	n, _ := new(big.Int).SetString(s[2:]+"1827"+s[:2], 10)
+	if new(big.Int).Mod(n, big.NewInt(97)).Int64() != 1 {
+		return "", fmt.Errorf("%w: %w", ErrInvalidIBAN, ErrInvalidChecksum)
+	}
+
+	return IBAN("IR" + s), nil
+}
+
+func (i IBAN) Validate() error {
+	_, err := ParseIBAN(string(i))
+	return err
+}
+
+// BankCode returns the three digit bank identifier that follows the check digits.
+func (i IBAN) BankCode() string {
+	if len(i) != 26 {
+		return ""
+	}
+	return string(i[4:7])
+}
+
+// AccountPart returns the nineteen digits after the bank code.
+func (i IBAN) AccountPart() string {
+	if len(i) != 26 {
+		return ""
+	}
+	return string(i[7:])
+}
+
+func (i IBAN) Bank() Bank {
+	if bank, ok := banksByIbanCode[i.BankCode()]; ok {
+		return bank
+	}
+	return UNKNOWN
+}
+
+func (i IBAN) String() string {
+	return string(i)
+}
+
+// NewIBAN builds an IBAN from a three digit bank code and a nineteen digit
+// account part, computing the check digits.
+func NewIBAN(bankCode, accountPart string) (IBAN, error) {
+	bban := bankCode + accountPart
+	if len(bankCode) != 3 || len(accountPart) != 19 || !isDigits(bban) {
+		return "", fmt.Errorf("%w: bank code must be 3 and account part 19 digits", ErrInvalidIBAN)
+	}
+	n, _ := new(big.Int).SetString(bban+"182700", 10)
+	check := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+	return IBAN(fmt.Sprintf("IR%02d%s", check, bban)), nil
+}
@@ -0,0 +1,64 @@
+package sanbod
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseIBAN(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     IBAN
+		wantErr  error
+		wantBank Bank
+	}{
+		{"IR820540102680020817909002", "IR820540102680020817909002", nil, ParsianBank},
+		{"ir82 0540 1026 8002 0817 9090 02", "IR820540102680020817909002", nil, ParsianBank},
+		{"820540102680020817909002", "IR820540102680020817909002", nil, ParsianBank},
+		{"IR۸۲۰۵۴۰۱۰۲۶۸۰۰۲۰۸۱۷۹۰۹۰۰۲", "IR820540102680020817909002", nil, ParsianBank},
+		{"IR160120000000001234567890", "IR160120000000001234567890", nil, BankMellat},
+		{"IR820540102680020817909003", "", ErrInvalidChecksum, ""},
+		{"IR830540102680020817909002", "", ErrInvalidChecksum, ""},
+		{"IR82054010268002081790900", "", ErrInvalidIBAN, ""},
+		{"DE89370400440532013000", "", ErrInvalidIBAN, ""},
+		{"IR82054010268002081790900A", "", ErrInvalidIBAN, ""},
+		{"", "", ErrInvalidIBAN, ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseIBAN(tt.in)
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+			t.Errorf("ParseIBAN(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseIBAN(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if err == nil && got.Bank() != tt.wantBank {
+			t.Errorf("ParseIBAN(%q).Bank() = %s, want %s", tt.in, got.Bank(), tt.wantBank)
+		}
+	}
+}
+
+func TestIBANParts(t *testing.T) {
+	i := IBAN("IR820540102680020817909002")
+	if i.BankCode() != "054" || i.AccountPart() != "0102680020817909002" {
+		t.Fatalf("got bank code %q and account part %q", i.BankCode(), i.AccountPart())
+	}
+	if IBAN("IR82").BankCode() != "" || IBAN("IR82").AccountPart() != "" || IBAN("IR82").Bank() != UNKNOWN {
+		t.Fatal("short iban should have no parts")
+	}
+
+	built, err := NewIBAN(i.BankCode(), i.AccountPart())
+	if err != nil || built != i {
+		t.Fatalf("NewIBAN = %q, %v", built, err)
+	}
+	unknown, err := NewIBAN("999", "0000000001234567890")
+	if err != nil || unknown.Validate() != nil || unknown.Bank() != UNKNOWN {
+		t.Fatalf("NewIBAN with unlisted bank code = %q, %v", unknown, err)
+	}
+	for _, args := range [][2]string{{"54", "0102680020817909002"}, {"054", "102680020817909002"}, {"05a", "0102680020817909002"}} {
+		if _, err := NewIBAN(args[0], args[1]); !errors.Is(err, ErrInvalidIBAN) {
+			t.Errorf("NewIBAN(%q, %q) error = %v", args[0], args[1], err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package sanbod
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type ImageKind string
+
+const (
+	ImageKindPhoto       ImageKind = "PHOTO"
+	ImageKindSignature   ImageKind = "SIGNATURE"
+	ImageKindFingerprint ImageKind = "FINGERPRINT"
+)
+
+var imageKindValues = map[string]ImageKind{
+	"PHOTO":       ImageKindPhoto,
+	"FACE":        ImageKindPhoto,
+	"PICTURE":     ImageKindPhoto,
+	"IMAGE":       ImageKindPhoto,
+	"عکس":         ImageKindPhoto,
+	"SIGNATURE":   ImageKindSignature,
+	"SIGN":        ImageKindSignature,
+	"امضا":        ImageKindSignature,
+	"FINGERPRINT": ImageKindFingerprint,
+	"اثر انگشت":   ImageKindFingerprint,
+}
+
+func (k ImageKind) IsKnown() bool {
+	return k == ImageKindPhoto || k == ImageKindSignature || k == ImageKindFingerprint
+}
+
+func (k ImageKind) String() string {
+	return string(k)
+}
+
+func (k *ImageKind) UnmarshalJSON(data []byte) error {
+	raw := enumText(data)
+	if v, ok := imageKindValues[enumKey(raw)]; ok {
+		*k = v
+		return nil
+	}
+	*k = ImageKind(raw)
+	return nil
+}
+
+type ImageFormat string
+
+const (
+	ImageFormatJPEG     ImageFormat = "jpeg"
+	ImageFormatPNG      ImageFormat = "png"
+	ImageFormatGIF      ImageFormat = "gif"
+	ImageFormatJPEG2000 ImageFormat = "jp2"
+	ImageFormatBMP      ImageFormat = "bmp"
+	ImageFormatWebP     ImageFormat = "webp"
+	ImageFormatUnknown  ImageFormat = "unknown"
+)
+
+func (f ImageFormat) Extension() string {
+	if f == ImageFormatJPEG {
+		return ".jpg"
+	}
+	if f == ImageFormatUnknown {
+		return ".bin"
+	}
+	return "." + string(f)
+}
+
+// Image returns the first image of the given kind.
+func (p PersonProfileWithImage) Image(kind ImageKind) (IdentityImage, bool) {
+	for _, img := range p.Images {
+		if img.Type == kind && img.Image != nil {
+			return img, true
+		}
+	}
+	return IdentityImage{}, false
+}
+
+// Reader streams the decoded image straight from the base64 payload, so the
+// image is never held in memory a second time.
+func (i IdentityImage) Reader() (io.Reader, error) {
+	if i.Image == nil || *i.Image == "" {
+		return nil, ErrNoImage
+	}
+	return base64.NewDecoder(base64.StdEncoding, strings.NewReader(i.payload())), nil
+}
+
+// Size returns the decoded size in bytes, computed without decoding.
+func (i IdentityImage) Size() int64 {
+	if i.Image == nil {
+		return 0
+	}
+	p := i.payload()
+	var n int64
+	padding := true
+	for j := len(p) - 1; j >= 0; j-- {
+		switch c := p[j]; {
+		case c == '\n' || c == '\r':
+		case c == '=' && padding:
+		default:
+			padding = false
+			n++
+		}
+	}
+	return n * 3 / 4
+}
+
+func (i IdentityImage) CheckSize(limit int64) error {
+	if size := i.Size(); size > limit {
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrImageTooLarge, size, limit)
+	}
+	return nil
+}
+
+func (i IdentityImage) Format() (ImageFormat, error) {
+	r, err := i.Reader()
+	if err != nil {
+		return "", err
+	}
+	head := make([]byte, 12)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return sniffImageFormat(head[:n]), nil
+}
+
+func (i IdentityImage) Bytes() ([]byte, error) {
+	r, err := i.Reader()
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, i.Size()))
+	_, err = buf.ReadFrom(r)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode decodes JPEG, PNG and GIF images. Other formats, such as the
+// JPEG 2000 the registry sometimes returns, give ErrUnsupportedImageFormat;
+// use WriteTo or Bytes for those.
+func (i IdentityImage) Decode() (image.Image, ImageFormat, error) {
+	format, err := i.Format()
+	if err != nil {
+		return nil, "", err
+	}
+	if format != ImageFormatJPEG && format != ImageFormatPNG && format != ImageFormatGIF {
+		return nil, format, fmt.Errorf("%w: %s", ErrUnsupportedImageFormat, format)
+	}
+	r, err := i.Reader()
+	if err != nil {
+		return nil, "", err
+	}
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, format, err
+	}
+	return img, format, nil
+}
+
+func (i IdentityImage) WriteTo(w io.Writer) (int64, error) {
+	r, err := i.Reader()
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(w, r)
+}
+
+// SaveFile writes the image into dir as name plus the extension of its
+// format. Anything but letters, digits, dash and underscore is dropped from
+// name; an empty name falls back to the image kind. Existing files are never
+// overwritten and the file is only readable by its owner.
+func (i IdentityImage) SaveFile(dir, name string) (path string, err error) {
+	format, err := i.Format()
+	if err != nil {
+		return "", err
+	}
+	name = safeFileName(name)
+	if name == "" {
+		name = safeFileName(strings.ToLower(string(i.Type)))
+	}
+	if name == "" {
+		name = "image"
+	}
+	path = filepath.Join(dir, name+format.Extension())
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	_, err = i.WriteTo(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// payload strips a data URI prefix such as "data:image/jpeg;base64,".
+func (i IdentityImage) payload() string {
+	s := *i.Image
+	if strings.HasPrefix(s, "data:") {
+		if idx := strings.Index(s, ","); idx >= 0 {
+			s = s[idx+1:]
+		}
+	}
+	return s
+}
+
+func sniffImageFormat(head []byte) ImageFormat {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xff, 0xd8, 0xff}):
+		return ImageFormatJPEG
+	case bytes.HasPrefix(head, []byte("\x89PNG")):
+		return ImageFormatPNG
+	case bytes.HasPrefix(head, []byte("GIF8")):
+		return ImageFormatGIF
+	case bytes.HasPrefix(head, []byte("\x00\x00\x00\x0cjP  ")), bytes.HasPrefix(head, []byte{0xff, 0x4f, 0xff, 0x51}):
+		return ImageFormatJPEG2000
+	case bytes.HasPrefix(head, []byte("BM")):
+		return ImageFormatBMP
+	case len(head) >= 12 && bytes.HasPrefix(head, []byte("RIFF")) && string(head[8:12]) == "WEBP":
+		return ImageFormatWebP
+	}
+	return ImageFormatUnknown
+}
+
+func safeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return -1
+	}, name)
+}
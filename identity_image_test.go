@@ -0,0 +1,86 @@
+package sanbod
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIdentityImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 3))); err != nil {
+		t.Fatal(err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var res InquiryUserProfileWithImage
+	err := json.Unmarshal([]byte(`{"message":{"firstName":"علی","images":[{"type":"face","image":"`+encoded+`"}]}}`), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, ok := res.Message.Image(ImageKindPhoto)
+	if !ok {
+		t.Fatalf("photo not found in %+v", res.Message.Images)
+	}
+
+	if img.Size() != int64(buf.Len()) {
+		t.Fatalf("size %d, want %d", img.Size(), buf.Len())
+	}
+	if err := img.CheckSize(10); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("expected ErrImageTooLarge, got %v", err)
+	}
+
+	decoded, format, err := img.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != ImageFormatPNG || decoded.Bounds().Dx() != 4 {
+		t.Fatalf("unexpected image %s %v", format, decoded.Bounds())
+	}
+
+	dir := t.TempDir()
+	path, err := img.SaveFile(dir, "../0499370899 photo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "0499370899photo.png") {
+		t.Fatalf("unexpected path %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(data, buf.Bytes()) {
+		t.Fatalf("saved file differs: %v", err)
+	}
+	if _, err := img.SaveFile(dir, "0499370899photo"); err == nil {
+		t.Fatal("expected existing file not to be overwritten")
+	}
+}
+
+func TestIdentityImageSize(t *testing.T) {
+	for n := range 8 {
+		raw := bytes.Repeat([]byte{0xab}, 50+n)
+		encoded := base64.StdEncoding.EncodeToString(raw)
+		wrapped := ""
+		for len(encoded) > 19 {
+			wrapped += encoded[:19] + "\r\n"
+			encoded = encoded[19:]
+		}
+		wrapped += encoded + "\n"
+
+		img := IdentityImage{Image: &wrapped}
+		if got := img.Size(); got != int64(len(raw)) {
+			t.Fatalf("size %d, want %d", got, len(raw))
+		}
+		if allocs := testing.AllocsPerRun(10, func() { img.Size() }); allocs != 0 {
+			t.Fatalf("Size allocated %v times", allocs)
+		}
+		data, err := img.Bytes()
+		if err != nil || !bytes.Equal(data, raw) {
+			t.Fatalf("decoded %x, %v", data, err)
+		}
+	}
+}
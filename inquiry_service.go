@@ -7,30 +7,48 @@ import (
 
 type InquiryUserProfileWithImageService struct {
 	c            *Client
-	nationalCode string
-	birthDate    string
+	nationalCode NationalCode
+	birthDate    JalaliDate
 }
 
-func (s *InquiryUserProfileWithImageService) NationalCode(nationalCode string) *InquiryUserProfileWithImageService {
+func (s *InquiryUserProfileWithImageService) NationalCode(nationalCode NationalCode) *InquiryUserProfileWithImageService {
 	s.nationalCode = nationalCode
 	return s
 }
 
-func (s *InquiryUserProfileWithImageService) Birthdate(birthDate string) *InquiryUserProfileWithImageService {
+func (s *InquiryUserProfileWithImageService) Birthdate(birthDate JalaliDate) *InquiryUserProfileWithImageService {
 	s.birthDate = birthDate
 	return s
 }
 
+func (s *InquiryUserProfileWithImageService) validated(raw bool) (in InquiryUserProfileWithImageService, err error) {
+	v := &validator{raw: raw}
+	in = *s
+	in.nationalCode = parseField(v, "nationalCode", s.nationalCode, true, ParseNationalCode)
+	v.jalaliDate("birthDate", s.birthDate)
+	return in, v.err()
+}
+
+func (s *InquiryUserProfileWithImageService) Validate() error {
+	_, err := s.validated(false)
+	return err
+}
+
 func (s *InquiryUserProfileWithImageService) Do(ctx context.Context, opts ...RequestOption) (res *InquiryUserProfileWithImage, err error) {
+	in, err := s.validated(!s.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/sanboom/v1/infoinquiry/personalwithimage",
+		endpoint: EndpointInquiryProfileWithImage,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"nationalId": s.nationalCode,
-		"birthDate":  s.birthDate,
+		"nationalId": string(in.nationalCode),
+		"birthDate":  in.birthDate.String(),
 	})
 
 	data, err := s.c.callAPI(ctx, r, opts...)
@@ -43,59 +61,67 @@ func (s *InquiryUserProfileWithImageService) Do(ctx context.Context, opts ...Req
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type InquiryUserProfileWithImage struct {
-	Error   bool `json:"error"`
-	Message struct {
-		FirstName      string `json:"firstName"`
-		LastName       string `json:"lastName"`
-		RegisterNo     string `json:"registerNo"`
-		RegisterSeries string `json:"registerSeries"`
-		RegisterSerial string `json:"registerSerial"`
-		NationalId     string `json:"nationalId"`
-		BirthDate      string `json:"birthDate"`
-		BirthPlace     string `json:"birthPlace"`
-		DeathStatus    string `json:"deathStatus"`
-		Gender         string `json:"gender"`
-		FatherName     string `json:"fatherName"`
-		Images         []struct {
-			Type  string  `json:"type"`
-			Image *string `json:"image"`
-		} `json:"images"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
+type IdentityImage struct {
+	Type  ImageKind `json:"type"`
+	Image *string   `json:"image"`
+}
+
+type PersonProfileWithImage struct {
+	PersonProfile
+	Images []IdentityImage `json:"images"`
 }
 
+type InquiryUserProfileWithImage = Response[PersonProfileWithImage]
+
 type InquiryUserProfileService struct {
 	c            *Client
-	nationalCode string
-	birthDate    string
+	nationalCode NationalCode
+	birthDate    JalaliDate
 }
 
-func (s *InquiryUserProfileService) NationalCode(nationalCode string) *InquiryUserProfileService {
+func (s *InquiryUserProfileService) NationalCode(nationalCode NationalCode) *InquiryUserProfileService {
 	s.nationalCode = nationalCode
 	return s
 }
 
-func (s *InquiryUserProfileService) Birthdate(birthDate string) *InquiryUserProfileService {
+func (s *InquiryUserProfileService) Birthdate(birthDate JalaliDate) *InquiryUserProfileService {
 	s.birthDate = birthDate
 	return s
 }
 
+func (s *InquiryUserProfileService) validated(raw bool) (in InquiryUserProfileService, err error) {
+	v := &validator{raw: raw}
+	in = *s
+	in.nationalCode = parseField(v, "nationalCode", s.nationalCode, true, ParseNationalCode)
+	v.jalaliDate("birthDate", s.birthDate)
+	return in, v.err()
+}
+
+func (s *InquiryUserProfileService) Validate() error {
+	_, err := s.validated(false)
+	return err
+}
+
 func (s *InquiryUserProfileService) Do(ctx context.Context, opts ...RequestOption) (res *InquiryUserProfile, err error) {
+	in, err := s.validated(!s.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/sanboom/v1/infoinquiry/personal",
+		endpoint: EndpointInquiryProfile,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"nationalId": s.nationalCode,
-		"birthDate":  s.birthDate,
+		"nationalId": string(in.nationalCode),
+		"birthDate":  in.birthDate.String(),
 	})
 
 	data, err := s.c.callAPI(ctx, r, opts...)
@@ -108,49 +134,64 @@ func (s *InquiryUserProfileService) Do(ctx context.Context, opts ...RequestOptio
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type InquiryUserProfile struct {
-	Error   bool `json:"error"`
-	Message struct {
-		FirstName      string `json:"firstName"`
-		LastName       string `json:"lastName"`
-		RegisterNo     string `json:"registerNo"`
-		RegisterSeries string `json:"registerSeries"`
-		RegisterSerial string `json:"registerSerial"`
-		NationalId     string `json:"nationalId"`
-		BirthDate      string `json:"birthDate"`
-		BirthPlace     string `json:"birthPlace"`
-		DeathStatus    string `json:"deathStatus"`
-		Gender         string `json:"gender"`
-		FatherName     string `json:"fatherName"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
+type PersonProfile struct {
+	FirstName      string       `json:"firstName"`
+	LastName       string       `json:"lastName"`
+	RegisterNo     string       `json:"registerNo"`
+	RegisterSeries string       `json:"registerSeries"`
+	RegisterSerial string       `json:"registerSerial"`
+	NationalId     string       `json:"nationalId"`
+	BirthDate      RegistryDate `json:"birthDate"`
+	BirthPlace     string       `json:"birthPlace"`
+	DeathStatus    DeathStatus  `json:"deathStatus"`
+	Gender         Gender       `json:"gender"`
+	FatherName     string       `json:"fatherName"`
 }
 
+type InquiryUserProfile = Response[PersonProfile]
+
 type IbanInquiryService struct {
 	c    *Client
-	iban string
+	iban IBAN
 }
 
-func (s *IbanInquiryService) Iban(iban string) *IbanInquiryService {
+func (s *IbanInquiryService) Iban(iban IBAN) *IbanInquiryService {
 	s.iban = iban
 
 	return s
 }
 
+func (s *IbanInquiryService) validated(raw bool) (in IbanInquiryService, err error) {
+	v := &validator{raw: raw}
+	in = *s
+	in.iban = parseField(v, "iban", s.iban, true, ParseIBAN)
+	return in, v.err()
+}
+
+func (s *IbanInquiryService) Validate() error {
+	_, err := s.validated(false)
+	return err
+}
+
 func (s *IbanInquiryService) Do(ctx context.Context, opts ...RequestOption) (res *IbanInquiry, err error) {
+	in, err := s.validated(!s.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/sanboom/v1/banksinquiry/ibaninquiry",
+		endpoint: EndpointIbanInquiry,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"iban": s.iban,
+		"iban": string(in.iban),
 	})
 
 	data, err := s.c.callAPI(ctx, r, opts...)
@@ -163,24 +204,94 @@ func (s *IbanInquiryService) Do(ctx context.Context, opts ...RequestOption) (res
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
+
+	return res, nil
+}
+
+type IbanOwner struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+type IbanInfo struct {
+	Iban               string        `json:"iban"`
+	BankName           Bank          `json:"bankName"`
+	DepositNumber      string        `json:"depositNumber"`
+	DepositStatus      DepositStatus `json:"depositStatus"`
+	DepositDescription string        `json:"depositDescription"`
+	DepositComment     string        `json:"depositComment"`
+	OwnersInfo         []IbanOwner   `json:"ownersInfo"`
+}
+
+type IbanInquiry = Response[IbanInfo]
+
+type CitizenshipVerificationService struct {
+	c            *Client
+	nationalCode NationalCode
+	birthDate    JalaliDate
+}
+
+func (s *CitizenshipVerificationService) NationalCode(nationalCode NationalCode) *CitizenshipVerificationService {
+	s.nationalCode = nationalCode
+	return s
+}
+
+func (s *CitizenshipVerificationService) Birthdate(birthDate JalaliDate) *CitizenshipVerificationService {
+	s.birthDate = birthDate
+	return s
+}
+
+func (s *CitizenshipVerificationService) validated(raw bool) (in CitizenshipVerificationService, err error) {
+	v := &validator{raw: raw}
+	in = *s
+	in.nationalCode = parseField(v, "nationalCode", s.nationalCode, true, ParseNationalCode)
+	v.jalaliDate("birthDate", s.birthDate)
+	return in, v.err()
+}
+
+func (s *CitizenshipVerificationService) Validate() error {
+	_, err := s.validated(false)
+	return err
+}
+
+func (s *CitizenshipVerificationService) Do(ctx context.Context, opts ...RequestOption) (res *CitizenshipVerification, err error) {
+	in, err := s.validated(!s.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	r := &request{
+		method:   http.MethodPost,
+		endpoint: EndpointCitizenshipVerification,
+		secType:  secTypeAccessToken,
+	}
+
+	r.setJsonParams(params{
+		"nationalId": string(in.nationalCode),
+		"birthDate":  in.birthDate.String(),
+	})
+
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res = new(CitizenshipVerification)
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return nil, err
+	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type IbanInquiry struct {
-	Error   bool `json:"error"`
-	Message struct {
-		Iban               string `json:"iban"`
-		BankName           string `json:"bankName"`
-		DepositNumber      string `json:"depositNumber"`
-		DepositStatus      string `json:"depositStatus"`
-		DepositDescription string `json:"depositDescription"`
-		DepositComment     string `json:"depositComment"`
-		OwnersInfo         []struct {
-			FirstName string `json:"firstName"`
-			LastName  string `json:"lastName"`
-		} `json:"ownersInfo"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
+type CitizenshipVerificationResult struct {
+	IsVerified  bool         `json:"isVerified"`
+	NationalId  string       `json:"nationalId"`
+	BirthDate   RegistryDate `json:"birthDate"`
+	DeathStatus DeathStatus  `json:"deathStatus"`
 }
+
+type CitizenshipVerification = Response[CitizenshipVerificationResult]
@@ -0,0 +1,73 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIbanInquiryService(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		"/sanboom/v1/banksinquiry/ibaninquiry": `{"error":false,"message":{"iban":"IR820540102680020817909002","bankName":"بانک پارسیان","depositNumber":"0102680020817909002","depositStatus":"02","ownersInfo":[{"firstName":"علی","lastName":"رضایی"}]},"result_number":1,"trace_id":"t5"}`,
+	})
+
+	res, err := c.NewIbanInquiryService().
+		Iban("IR82 0540 1026 8002 0817 9090 02").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message.BankName != ParsianBank || !res.Message.DepositStatus.IsActive() {
+		t.Fatalf("unexpected response %+v", res.Message)
+	}
+	if len(res.Message.OwnersInfo) != 1 || res.Message.OwnersInfo[0].LastName != "رضایی" {
+		t.Fatalf("unexpected owners %+v", res.Message.OwnersInfo)
+	}
+	if got := f.bodies["/sanboom/v1/banksinquiry/ibaninquiry"]["iban"]; got != "IR820540102680020817909002" {
+		t.Fatalf("iban sent as %v", got)
+	}
+}
+
+func TestCitizenshipVerificationService(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		"/sanboom/v1/infoinquiry/citizenshipverification": `{"error":false,"message":{"isVerified":true,"nationalId":"0499370899","birthDate":"1370/05/12","deathStatus":"زنده"},"result_number":1,"trace_id":"t6"}`,
+	})
+
+	res, err := c.NewCitizenshipVerificationService().
+		NationalCode("۰۴۹۹۳۷۰۸۹۹").
+		Birthdate(JalaliDateOf(time.Date(1991, time.August, 3, 0, 0, 0, 0, time.UTC))).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Message.IsVerified || !res.Message.DeathStatus.IsAlive() || res.TraceId != "t6" {
+		t.Fatalf("unexpected response %+v", res)
+	}
+	if res.Message.BirthDate.JalaliDate != (JalaliDate{Year: 1370, Month: 5, Day: 12}) {
+		t.Fatalf("unexpected birth date %v", res.Message.BirthDate)
+	}
+
+	body := f.bodies["/sanboom/v1/infoinquiry/citizenshipverification"]
+	if body["nationalId"] != "0499370899" || body["birthDate"] != "1370/05/12" {
+		t.Fatalf("unexpected request body %v", body)
+	}
+}
+
+func TestCitizenshipVerificationServiceValidation(t *testing.T) {
+	f, c := newFakeServer(t, nil)
+
+	_, err := c.NewCitizenshipVerificationService().
+		NationalCode("1111111111").
+		Do(context.Background())
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 2 {
+		t.Fatalf("expected two field errors, got %v", err)
+	}
+	if verr.Fields[0].Field != "nationalCode" || verr.Fields[1].Field != "birthDate" || verr.Fields[1].Reason != ReasonMissing {
+		t.Fatalf("unexpected field errors %+v", verr.Fields)
+	}
+	if len(f.calls) != 0 {
+		t.Fatalf("expected no http calls, got %v", f.calls)
+	}
+}
@@ -0,0 +1,223 @@
+package sanbod
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JalaliDate is a Solar Hijri calendar date as used by the civil registry.
+// It is sent to and read from the API in the yyyy/mm/dd layout.
+type JalaliDate struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+func NewJalaliDate(year, month, day int) (JalaliDate, error) {
+	d := JalaliDate{Year: year, Month: month, Day: day}
+	if err := d.Validate(); err != nil {
+		return JalaliDate{}, err
+	}
+	return d, nil
+}
+
+// JalaliDateOf converts the calendar day of t, in t's location, to the Solar Hijri calendar.
+func JalaliDateOf(t time.Time) JalaliDate {
+	y, m, d := t.Date()
+	return jalaliFromDays(daysSinceEpoch(y, int(m), d))
+}
+
+// ParseJalaliDate accepts yyyy/mm/dd, yyyy-mm-dd and yyyymmdd, in ASCII or Persian digits.
+func ParseJalaliDate(s string) (JalaliDate, error) {
+	s = Normalize(s)
+
+	var parts []string
+	switch {
+	case strings.Contains(s, "/"):
+		parts = strings.Split(s, "/")
+	case strings.Contains(s, "-"):
+		parts = strings.Split(s, "-")
+	case len(s) == 8:
+		parts = []string{s[:4], s[4:6], s[6:]}
+	}
+	if len(parts) != 3 {
+		return JalaliDate{}, fmt.Errorf("%w: %q is not in yyyy/mm/dd layout", ErrInvalidJalaliDate, s)
+	}
+
+	var n [3]int
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if p == "" || err != nil || !isDigits(p) {
+			return JalaliDate{}, fmt.Errorf("%w: %q is not in yyyy/mm/dd layout", ErrInvalidJalaliDate, s)
+		}
+		n[i] = v
+	}
+
+	return NewJalaliDate(n[0], n[1], n[2])
+}
+
+func (d JalaliDate) Validate() error {
+	if d.Year < 1 || d.Year >= jalaliBreaks[len(jalaliBreaks)-1] {
+		return fmt.Errorf("%w: year %d out of range", ErrInvalidJalaliDate, d.Year)
+	}
+	if d.Month < 1 || d.Month > 12 {
+		return fmt.Errorf("%w: month %d out of range", ErrInvalidJalaliDate, d.Month)
+	}
+	if d.Day < 1 || d.Day > jalaliMonthLength(d.Year, d.Month) {
+		return fmt.Errorf("%w: %d/%02d has no day %d", ErrInvalidJalaliDate, d.Year, d.Month, d.Day)
+	}
+	return nil
+}
+
+func (d JalaliDate) IsZero() bool {
+	return d == JalaliDate{}
+}
+
+// Time returns midnight of the date in loc, or in UTC when loc is nil.
+func (d JalaliDate) Time(loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	y, m, day := civilFromDays(d.days())
+	return time.Date(y, time.Month(m), day, 0, 0, 0, 0, loc)
+}
+
+func (d JalaliDate) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d/%02d/%02d", d.Year, d.Month, d.Day)
+}
+
+func (d JalaliDate) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *JalaliDate) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = JalaliDate{}
+		return nil
+	}
+	v, err := ParseJalaliDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// RegistryDate is a date read from a response. The registry has records
+// with dates such as 1340/00/00, so a value that does not parse leaves
+// JalaliDate zero instead of failing the whole response; Raw always holds the
+// value as received.
+type RegistryDate struct {
+	JalaliDate
+	Raw string
+}
+
+func (d RegistryDate) MarshalText() ([]byte, error) {
+	if d.JalaliDate.IsZero() {
+		return []byte(d.Raw), nil
+	}
+	return d.JalaliDate.MarshalText()
+}
+
+func (d *RegistryDate) UnmarshalText(text []byte) error {
+	d.Raw = string(text)
+	d.JalaliDate, _ = ParseJalaliDate(d.Raw)
+	return nil
+}
+
+func IsJalaliLeapYear(year int) bool {
+	leap, _, _ := jalaliCal(year)
+	return leap == 0
+}
+
+func jalaliMonthLength(year, month int) int {
+	switch {
+	case month <= 6:
+		return 31
+	case month <= 11:
+		return 30
+	case IsJalaliLeapYear(year):
+		return 30
+	}
+	return 29
+}
+
+// The conversion below follows the jalaali-js algorithm by Borkowski,
+// working on days since the Unix epoch instead of Julian day numbers.
+
+var jalaliBreaks = []int{
+	-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210,
+	1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178,
+}
+
+// jalaliCal returns the position of year in its leap cycle (0 for a leap
+// year), the Gregorian year in which it starts and the March day of Nowruz.
+func jalaliCal(jy int) (leap, gy, march int) {
+	gy = jy + 621
+	leapJ := -14
+	jp := jalaliBreaks[0]
+	jump := 0
+	for _, jm := range jalaliBreaks[1:] {
+		jump = jm - jp
+		if jy < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := jy - jp
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	march = 20 + leapJ - leapG
+
+	if jump-n < 6 {
+		n = n - jump + (jump+4)/33*33
+	}
+	leap = ((n+1)%33 - 1) % 4
+	if leap == -1 {
+		leap = 4
+	}
+	return leap, gy, march
+}
+
+func (d JalaliDate) days() int {
+	_, gy, march := jalaliCal(d.Year)
+	return daysSinceEpoch(gy, 3, march) + (d.Month-1)*31 - d.Month/7*(d.Month-7) + d.Day - 1
+}
+
+func jalaliFromDays(days int) JalaliDate {
+	gy, _, _ := civilFromDays(days)
+	jy := gy - 621
+	leap, _, march := jalaliCal(jy)
+	k := days - daysSinceEpoch(gy, 3, march)
+	if k >= 0 {
+		if k <= 185 {
+			return JalaliDate{Year: jy, Month: 1 + k/31, Day: k%31 + 1}
+		}
+		k -= 186
+	} else {
+		jy--
+		k += 179
+		if leap == 1 {
+			k++
+		}
+	}
+	return JalaliDate{Year: jy, Month: 7 + k/30, Day: k%30 + 1}
+}
+
+func daysSinceEpoch(y, m, d int) int {
+	return int(time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+func civilFromDays(days int) (y, m, d int) {
+	t := time.Unix(int64(days)*86400, 0).UTC()
+	return t.Year(), int(t.Month()), t.Day()
+}
@@ -0,0 +1,126 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJalaliDateConversion(t *testing.T) {
+	tests := []struct {
+		jalali    JalaliDate
+		gregorian string
+	}{
+		{JalaliDate{1348, 10, 11}, "1970-01-01"},
+		{JalaliDate{1357, 11, 22}, "1979-02-11"},
+		{JalaliDate{1370, 5, 12}, "1991-08-03"},
+		{JalaliDate{1399, 12, 30}, "2021-03-20"},
+		{JalaliDate{1400, 1, 1}, "2021-03-21"},
+		{JalaliDate{1403, 1, 1}, "2024-03-20"},
+		{JalaliDate{1403, 12, 30}, "2025-03-20"},
+		{JalaliDate{1404, 1, 1}, "2025-03-21"},
+	}
+	for _, tt := range tests {
+		g, _ := time.Parse(time.DateOnly, tt.gregorian)
+		if got := tt.jalali.Time(nil); !got.Equal(g) {
+			t.Errorf("%v.Time() = %s, want %s", tt.jalali, got.Format(time.DateOnly), tt.gregorian)
+		}
+		if got := JalaliDateOf(g); got != tt.jalali {
+			t.Errorf("JalaliDateOf(%s) = %v, want %v", tt.gregorian, got, tt.jalali)
+		}
+	}
+}
+
+func TestIsJalaliLeapYear(t *testing.T) {
+	for _, y := range []int{1370, 1375, 1379, 1395, 1399, 1403, 1408} {
+		if !IsJalaliLeapYear(y) {
+			t.Errorf("%d should be a leap year", y)
+		}
+		if _, err := NewJalaliDate(y, 12, 30); err != nil {
+			t.Errorf("%d/12/30 should exist: %v", y, err)
+		}
+	}
+	for _, y := range []int{1371, 1374, 1400, 1402, 1404, 1407} {
+		if IsJalaliLeapYear(y) {
+			t.Errorf("%d should not be a leap year", y)
+		}
+		if _, err := NewJalaliDate(y, 12, 30); !errors.Is(err, ErrInvalidJalaliDate) {
+			t.Errorf("%d/12/30 should not exist, got %v", y, err)
+		}
+	}
+}
+
+func TestParseJalaliDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    JalaliDate
+		wantErr bool
+	}{
+		{"1370/05/12", JalaliDate{1370, 5, 12}, false},
+		{"1370-5-12", JalaliDate{1370, 5, 12}, false},
+		{"13700512", JalaliDate{1370, 5, 12}, false},
+		{"۱۳۷۰/۰۵/۱۲", JalaliDate{1370, 5, 12}, false},
+		{"1340/00/00", JalaliDate{}, true},
+		{"1370/07/31", JalaliDate{}, true},
+		{"1370/5", JalaliDate{}, true},
+		{"1403/01/01/", JalaliDate{}, true},
+		{"1403//01/01", JalaliDate{}, true},
+		{"/1403/01/01", JalaliDate{}, true},
+		{"1403/01-01", JalaliDate{}, true},
+		{"1403--01-01", JalaliDate{}, true},
+		{"1370/05/1a", JalaliDate{}, true},
+		{"", JalaliDate{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseJalaliDate(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseJalaliDate(%q) = %v, %v", tt.in, got, err)
+		}
+		if tt.wantErr && !errors.Is(err, ErrInvalidJalaliDate) {
+			t.Errorf("ParseJalaliDate(%q) error = %v, want ErrInvalidJalaliDate", tt.in, err)
+		}
+	}
+}
+
+func TestJalaliDateDecoding(t *testing.T) {
+	var req struct {
+		BirthDate JalaliDate `json:"birth_date"`
+	}
+	err := json.Unmarshal([]byte(`{"birth_date":"1340/00/00"}`), &req)
+	if err == nil {
+		t.Fatalf("inputs should be decoded strictly, got %v", req.BirthDate)
+	}
+
+	var res struct {
+		BirthDate RegistryDate `json:"birthDate"`
+	}
+	err = json.Unmarshal([]byte(`{"birthDate":"1340/00/00"}`), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.BirthDate.IsZero() || res.BirthDate.Raw != "1340/00/00" {
+		t.Fatalf("unexpected registry date %+v", res.BirthDate)
+	}
+	data, _ := json.Marshal(res)
+	if string(data) != `{"birthDate":"1340/00/00"}` {
+		t.Fatalf("expected the raw value back, got %s", data)
+	}
+}
+
+func TestInquiryUserProfileOddBirthDate(t *testing.T) {
+	_, c := newFakeServer(t, map[string]string{
+		EndpointInquiryProfile: `{"error":false,"message":{"firstName":"علی","lastName":"رضایی","birthDate":"1340/00/00"},"trace_id":"t1"}`,
+	})
+
+	res, err := c.NewInquiryUserProfileService().
+		NationalCode("0499370899").
+		Birthdate(JalaliDate{1340, 1, 1}).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Message.FirstName != "علی" || res.Message.BirthDate.Raw != "1340/00/00" || !res.Message.BirthDate.IsZero() {
+		t.Fatalf("unexpected profile %+v", res.Message)
+	}
+}
@@ -0,0 +1,284 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type KYCCheck string
+
+const (
+	// KYCCheckProfile passes when the registry returns the person and does
+	// not record them as dead.
+	KYCCheckProfile     KYCCheck = "profile"
+	KYCCheckMobileMatch KYCCheck = "mobile_match"
+	KYCCheckCardMatch   KYCCheck = "card_match"
+	KYCCheckCardIban    KYCCheck = "card_iban"
+)
+
+type KYCStatus string
+
+const (
+	KYCStatusPassed KYCStatus = "passed"
+	KYCStatusFailed KYCStatus = "failed"
+	KYCStatusError  KYCStatus = "error"
+)
+
+type KYCCheckResult struct {
+	Check    KYCCheck         `json:"check"`
+	Status   KYCStatus        `json:"status"`
+	Err      error            `json:"-"`
+	TraceId  string           `json:"trace_id"`
+	Duration time.Duration    `json:"duration"`
+	Metadata ResponseMetadata `json:"metadata"`
+}
+
+type KYCReport struct {
+	Checks      []KYCCheckResult                   `json:"checks"`
+	Profile     *InquiryUserProfile                `json:"profile,omitempty"`
+	MobileMatch *MatchNationalCodeWithMobileNumber `json:"mobile_match,omitempty"`
+	CardMatch   *MatchNationalCodeWithCardNumber   `json:"card_match,omitempty"`
+	CardIban    *CardToIban                        `json:"card_iban,omitempty"`
+	Duration    time.Duration                      `json:"duration"`
+}
+
+// Passed reports whether every check that ran passed.
+func (r *KYCReport) Passed() bool {
+	for _, c := range r.Checks {
+		if c.Status != KYCStatusPassed {
+			return false
+		}
+	}
+	return len(r.Checks) > 0
+}
+
+// Incomplete reports whether a check could not be decided because its call
+// failed; Passed is then false without the person having failed anything.
+func (r *KYCReport) Incomplete() bool {
+	for _, c := range r.Checks {
+		if c.Status == KYCStatusError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err joins the errors of the checks whose call failed, or returns nil.
+func (r *KYCReport) Err() error {
+	var errs []error
+	for _, c := range r.Checks {
+		if c.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Check, c.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *KYCReport) Check(check KYCCheck) (KYCCheckResult, bool) {
+	for _, c := range r.Checks {
+		if c.Check == check {
+			return c, true
+		}
+	}
+	return KYCCheckResult{}, false
+}
+
+// KYCService runs the inquiries that the given identifiers allow, all at
+// once: the profile needs a national code and birth date, the mobile and card
+// matches need a national code with the mobile or card number, and the card
+// to IBAN lookup needs only the card number.
+type KYCService struct {
+	c            *Client
+	nationalCode NationalCode
+	birthDate    JalaliDate
+	mobileNumber MobileNumber
+	cardNumber   CardNumber
+	checks       []KYCCheck
+}
+
+func (c *Client) NewKYCService() *KYCService {
+	return &KYCService{c: c}
+}
+
+func (s *KYCService) NationalCode(nationalCode NationalCode) *KYCService {
+	s.nationalCode = nationalCode
+	return s
+}
+
+func (s *KYCService) Birthdate(birthDate JalaliDate) *KYCService {
+	s.birthDate = birthDate
+	return s
+}
+
+func (s *KYCService) MobileNumber(mobileNumber MobileNumber) *KYCService {
+	s.mobileNumber = mobileNumber
+	return s
+}
+
+func (s *KYCService) CardNumber(cardNumber CardNumber) *KYCService {
+	s.cardNumber = cardNumber
+	return s
+}
+
+// Checks limits the run to the given checks, for example to repeat only the
+// ones that errored in an earlier report. All checks the identifiers allow
+// run when none are given.
+func (s *KYCService) Checks(checks ...KYCCheck) *KYCService {
+	s.checks = checks
+	return s
+}
+
+func (s *KYCService) validated(raw bool) (in KYCService, err error) {
+	v := &validator{raw: raw}
+	in = *s
+	in.nationalCode = parseField(v, "nationalCode", s.nationalCode, s.cardNumber == "", ParseNationalCode)
+	in.mobileNumber = parseField(v, "mobileNumber", s.mobileNumber, false, ParseMobileNumber)
+	in.cardNumber = parseField(v, "cardNumber", s.cardNumber, false, ParseCardNumber)
+	if !s.birthDate.IsZero() {
+		v.jalaliDate("birthDate", s.birthDate)
+	}
+	for _, check := range s.checks {
+		switch check {
+		case KYCCheckProfile, KYCCheckMobileMatch, KYCCheckCardMatch, KYCCheckCardIban:
+		default:
+			v.invalid("checks", fmt.Errorf("unknown check %q", check))
+		}
+	}
+	return in, v.err()
+}
+
+func (s *KYCService) Validate() error {
+	_, err := s.validated(false)
+	return err
+}
+
+// Do returns an error only when the input is invalid. Failed calls are
+// reported per check and do not affect the others; see KYCReport.Incomplete
+// and KYCReport.Err. Each check records its own metadata in its result, and
+// when a traceid is set by WithTraceId or the context each check sends it
+// suffixed with the check name, so WithMetadata and WithTraceId do not apply
+// to the calls as they are.
+func (s *KYCService) Do(ctx context.Context, opts ...RequestOption) (res *KYCReport, err error) {
+	in, err := s.validated(!s.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	res = new(KYCReport)
+	type run struct {
+		check KYCCheck
+		do    func(opts []RequestOption) (passed bool, traceId string, err error)
+	}
+	var runs []run
+
+	if in.nationalCode != "" && !in.birthDate.IsZero() {
+		runs = append(runs, run{KYCCheckProfile, func(opts []RequestOption) (bool, string, error) {
+			r, err := s.c.NewInquiryUserProfileService().
+				NationalCode(in.nationalCode).
+				Birthdate(in.birthDate).
+				Do(ctx, opts...)
+			if err != nil {
+				return false, "", err
+			}
+			res.Profile = r
+			if err := r.Err(); err != nil {
+				return false, "", err
+			}
+			return !r.Message.DeathStatus.IsDead(), r.RequestTraceId, nil
+		}})
+	}
+	if in.nationalCode != "" && in.mobileNumber != "" {
+		runs = append(runs, run{KYCCheckMobileMatch, func(opts []RequestOption) (bool, string, error) {
+			r, err := s.c.NewMatchNationalCodeWithMobileNumberService().
+				NationalCode(in.nationalCode).
+				MobileNumber(in.mobileNumber).
+				Do(ctx, opts...)
+			if err != nil {
+				return false, "", err
+			}
+			res.MobileMatch = r
+			if err := r.Err(); err != nil {
+				return false, "", err
+			}
+			return r.Message.IsMatched, r.RequestTraceId, nil
+		}})
+	}
+	if in.nationalCode != "" && in.cardNumber != "" {
+		runs = append(runs, run{KYCCheckCardMatch, func(opts []RequestOption) (bool, string, error) {
+			r, err := s.c.NewMatchNationalCodeWithCardNumberService().
+				NationalCode(in.nationalCode).
+				CardNumber(in.cardNumber).
+				MobileNumber(in.mobileNumber).
+				Do(ctx, opts...)
+			if err != nil {
+				return false, "", err
+			}
+			res.CardMatch = r
+			if err := r.Err(); err != nil {
+				return false, "", err
+			}
+			return r.Message.IsMatched, r.RequestTraceId, nil
+		}})
+	}
+	if in.cardNumber != "" {
+		runs = append(runs, run{KYCCheckCardIban, func(opts []RequestOption) (bool, string, error) {
+			r, err := s.c.NewCardToIbanService().
+				CardNumber(in.cardNumber).
+				Do(ctx, opts...)
+			if err != nil {
+				return false, "", err
+			}
+			res.CardIban = r
+			if err := r.Err(); err != nil {
+				return false, "", err
+			}
+			return r.Message.Iban != "", r.RequestTraceId, nil
+		}})
+	}
+
+	if len(in.checks) > 0 {
+		runs = slices.DeleteFunc(runs, func(r run) bool { return !slices.Contains(in.checks, r.check) })
+	}
+
+	traceId := callerTraceId(ctx, opts)
+	start := time.Now()
+	res.Checks = make([]KYCCheckResult, len(runs))
+	var wg sync.WaitGroup
+	for i, run := range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := KYCCheckResult{Check: run.check}
+			checkOpts := append(opts[:len(opts):len(opts)], WithMetadata(&result.Metadata))
+			if traceId != "" {
+				checkOpts = append(checkOpts, WithTraceId(traceId+"-"+string(run.check)))
+			}
+
+			began := time.Now()
+			passed, id, err := run.do(checkOpts)
+			result.Duration = time.Since(began)
+			switch {
+			case err != nil:
+				result.Status = KYCStatusError
+				result.Err = err
+				result.TraceId = result.Metadata.TraceId
+			case passed:
+				result.Status = KYCStatusPassed
+				result.TraceId = id
+			default:
+				result.Status = KYCStatusFailed
+				result.TraceId = id
+			}
+			res.Checks[i] = result
+		}()
+	}
+	wg.Wait()
+	res.Duration = time.Since(start)
+
+	return res, nil
+}
@@ -0,0 +1,198 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKYCServicePartialFailure(t *testing.T) {
+	_, c := newFakeServer(t, map[string]string{
+		EndpointInquiryProfile: `{"error":false,"message":{"firstName":"علی","lastName":"رضایی","deathStatus":"زنده"}}`,
+		EndpointMatchMobile:    `{"error":false,"message":{"isMatched":true}}`,
+		EndpointCardToIban:     `{"error":false,"message":{"iban":"IR820540102680020817909002"}}`,
+	})
+
+	ctx := ContextWithTraceId(context.Background(), "run-1")
+	res, err := c.NewKYCService().
+		NationalCode("0499370899").
+		Birthdate(JalaliDate{1370, 5, 12}).
+		MobileNumber("09123456789").
+		CardNumber("6219861234567898").
+		Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[KYCCheck]struct {
+		status   KYCStatus
+		endpoint string
+	}{
+		KYCCheckProfile:     {KYCStatusPassed, EndpointInquiryProfile},
+		KYCCheckMobileMatch: {KYCStatusPassed, EndpointMatchMobile},
+		KYCCheckCardMatch:   {KYCStatusError, EndpointMatchCard},
+		KYCCheckCardIban:    {KYCStatusPassed, EndpointCardToIban},
+	}
+	if len(res.Checks) != len(want) {
+		t.Fatalf("expected %d checks, got %+v", len(want), res.Checks)
+	}
+	for _, check := range res.Checks {
+		w := want[check.Check]
+		if check.Status != w.status || check.Metadata.Endpoint != w.endpoint {
+			t.Errorf("check %s: status %s endpoint %s, want %s %s", check.Check, check.Status, check.Metadata.Endpoint, w.status, w.endpoint)
+		}
+		if id := "run-1-" + string(check.Check); check.TraceId != id || check.Metadata.TraceId != id {
+			t.Errorf("check %s: traceid %q metadata %q, want %q", check.Check, check.TraceId, check.Metadata.TraceId, id)
+		}
+	}
+
+	if res.Passed() || !res.Incomplete() {
+		t.Fatalf("a failed call should leave the report incomplete, not passed")
+	}
+	if !IsAPIError(res.Err()) || !strings.HasPrefix(res.Err().Error(), "card_match: ") {
+		t.Fatalf("unexpected report error %v", res.Err())
+	}
+	if res.Profile == nil || res.MobileMatch == nil || res.CardIban == nil || res.CardMatch != nil {
+		t.Fatalf("unexpected responses %+v", res)
+	}
+}
+
+func TestKYCServiceDeadPerson(t *testing.T) {
+	_, c := newFakeServer(t, map[string]string{
+		EndpointInquiryProfile: `{"error":false,"message":{"firstName":"علی","lastName":"رضایی","deathStatus":"فوت شده"}}`,
+	})
+
+	res, err := c.NewKYCService().
+		NationalCode("0499370899").
+		Birthdate(JalaliDate{1370, 5, 12}).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	check, ok := res.Check(KYCCheckProfile)
+	if !ok || check.Status != KYCStatusFailed || res.Incomplete() || res.Err() != nil {
+		t.Fatalf("unexpected report %+v", res.Checks)
+	}
+}
+
+func TestKYCServiceErrorEnvelopes(t *testing.T) {
+	_, c := newFakeServer(t, map[string]string{
+		EndpointInquiryProfile: `{"error":true,"message":{},"result_number":-3,"trace_id":"upstream-1"}`,
+		EndpointMatchMobile:    `{"error":true,"message":{"isMatched":false},"result_number":-7}`,
+	})
+
+	ctx := ContextWithTraceId(context.Background(), "run-2")
+	res, err := c.NewKYCService().
+		NationalCode("0499370899").
+		Birthdate(JalaliDate{1370, 5, 12}).
+		MobileNumber("09123456789").
+		Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for check, code := range map[KYCCheck]int64{KYCCheckProfile: -3, KYCCheckMobileMatch: -7} {
+		result, ok := res.Check(check)
+		if !ok || result.Status != KYCStatusError {
+			t.Fatalf("%s: expected an error, got %+v", check, result)
+		}
+		var aPIError *APIError
+		if !errors.As(result.Err, &aPIError) || aPIError.ResultNumber != code || aPIError.RequestTraceId != "run-2-"+string(check) {
+			t.Fatalf("%s: unexpected error %#v", check, result.Err)
+		}
+		if !IsRetryable(result.Err) || result.TraceId != "run-2-"+string(check) {
+			t.Fatalf("%s: unexpected result %+v", check, result)
+		}
+	}
+	if !res.Incomplete() || res.Err() == nil || res.Passed() {
+		t.Fatalf("error envelopes should leave the run incomplete, got %+v", res.Checks)
+	}
+}
+
+func TestKYCServiceRunsChecksConcurrently(t *testing.T) {
+	c := NewClient("username", "password")
+	c.cache.set(CacheAccessToken, "token")
+
+	responses := map[string]string{
+		EndpointInquiryProfile: `{"error":false,"message":{"firstName":"علی"}}`,
+		EndpointMatchMobile:    `{"error":false,"message":{"isMatched":true}}`,
+		EndpointMatchCard:      `{"error":false,"message":{"isMatched":true}}`,
+		EndpointCardToIban:     `{"error":false,"message":{"iban":"IR820540102680020817909002"}}`,
+	}
+	var (
+		mu      sync.Mutex
+		arrived int
+		all     = make(chan struct{})
+	)
+	c.do = func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		arrived++
+		if arrived == len(responses) {
+			close(all)
+		}
+		mu.Unlock()
+
+		select {
+		case <-all:
+		case <-time.After(5 * time.Second):
+			return nil, fmt.Errorf("%s was not called alongside the others", req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(responses[req.URL.Path])),
+		}, nil
+	}
+
+	res, err := c.NewKYCService().
+		NationalCode("0499370899").
+		Birthdate(JalaliDate{1370, 5, 12}).
+		MobileNumber("09123456789").
+		CardNumber("6219861234567898").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Passed() || res.Err() != nil {
+		t.Fatalf("expected every check to pass, got %+v", res.Checks)
+	}
+	ids := map[string]bool{}
+	for _, check := range res.Checks {
+		ids[check.TraceId] = true
+	}
+	if len(ids) != len(res.Checks) {
+		t.Fatalf("expected a traceid per check, got %+v", res.Checks)
+	}
+}
+
+func TestKYCServiceChecks(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointInquiryProfile: `{"error":false,"message":{"firstName":"علی","deathStatus":"زنده"}}`,
+		EndpointMatchMobile:    `{"error":false,"message":{"isMatched":true}}`,
+	})
+
+	res, err := c.RunKYC(context.Background(), KYCRequest{
+		NationalCode: "0499370899",
+		BirthDate:    JalaliDate{1370, 5, 12},
+		MobileNumber: "09123456789",
+		Checks:       []KYCCheck{KYCCheckMobileMatch},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Checks) != 1 || res.Checks[0].Check != KYCCheckMobileMatch || f.calls[EndpointInquiryProfile] != 0 {
+		t.Fatalf("expected only the mobile match to run, got %+v and %v", res.Checks, f.calls)
+	}
+
+	_, err = c.NewKYCService().NationalCode("0499370899").Checks("address").Do(context.Background())
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected an unknown check to be rejected, got %v", err)
+	}
+}
@@ -7,30 +7,48 @@ import (
 
 type MatchNationalCodeWithMobileNumberService struct {
 	c            *Client
-	mobileNumber string
-	nationalCode string
+	mobileNumber MobileNumber
+	nationalCode NationalCode
 }
 
-func (s *MatchNationalCodeWithMobileNumberService) MobileNumber(mobileNumber string) *MatchNationalCodeWithMobileNumberService {
+func (s *MatchNationalCodeWithMobileNumberService) MobileNumber(mobileNumber MobileNumber) *MatchNationalCodeWithMobileNumberService {
 	s.mobileNumber = mobileNumber
 	return s
 }
 
-func (s *MatchNationalCodeWithMobileNumberService) NationalCode(nationalCode string) *MatchNationalCodeWithMobileNumberService {
+func (s *MatchNationalCodeWithMobileNumberService) NationalCode(nationalCode NationalCode) *MatchNationalCodeWithMobileNumberService {
 	s.nationalCode = nationalCode
 	return s
 }
 
+func (s *MatchNationalCodeWithMobileNumberService) validated(raw bool) (in MatchNationalCodeWithMobileNumberService, err error) {
+	v := &validator{raw: raw}
+	in = *s
+	in.mobileNumber = parseField(v, "mobileNumber", s.mobileNumber, true, ParseMobileNumber)
+	in.nationalCode = parseField(v, "nationalCode", s.nationalCode, true, ParseNationalCode)
+	return in, v.err()
+}
+
+func (s *MatchNationalCodeWithMobileNumberService) Validate() error {
+	_, err := s.validated(false)
+	return err
+}
+
 func (s *MatchNationalCodeWithMobileNumberService) Do(ctx context.Context, opts ...RequestOption) (res *MatchNationalCodeWithMobileNumber, err error) {
+	in, err := s.validated(!s.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/sanboom/v1/infomatching/mobilenationalid",
+		endpoint: EndpointMatchMobile,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"mobileNumber": s.mobileNumber,
-		"nationalId":   s.nationalCode,
+		"mobileNumber": string(in.mobileNumber),
+		"nationalId":   string(in.nationalCode),
 	})
 
 	data, err := s.c.callAPI(ctx, r, opts...)
@@ -43,52 +61,69 @@ func (s *MatchNationalCodeWithMobileNumberService) Do(ctx context.Context, opts
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type MatchNationalCodeWithMobileNumber struct {
-	Error   bool `json:"error"`
-	Message struct {
-		IsMatched bool `json:"ismatched"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
+type MatchResult struct {
+	IsMatched bool `json:"ismatched"`
 }
 
+type MatchNationalCodeWithMobileNumber = Response[MatchResult]
+
 type MatchNationalCodeWithCardNumberService struct {
 	c            *Client
-	mobileNumber string
-	nationalCode string
-	cardNumber   string
+	mobileNumber MobileNumber
+	nationalCode NationalCode
+	cardNumber   CardNumber
 }
 
-func (s *MatchNationalCodeWithCardNumberService) MobileNumber(mobileNumber string) *MatchNationalCodeWithCardNumberService {
+func (s *MatchNationalCodeWithCardNumberService) MobileNumber(mobileNumber MobileNumber) *MatchNationalCodeWithCardNumberService {
 	s.mobileNumber = mobileNumber
 	return s
 }
 
-func (s *MatchNationalCodeWithCardNumberService) NationalCode(nationalCode string) *MatchNationalCodeWithCardNumberService {
+func (s *MatchNationalCodeWithCardNumberService) NationalCode(nationalCode NationalCode) *MatchNationalCodeWithCardNumberService {
 	s.nationalCode = nationalCode
 	return s
 }
 
-func (s *MatchNationalCodeWithCardNumberService) CardNumber(cardNumber string) *MatchNationalCodeWithCardNumberService {
+func (s *MatchNationalCodeWithCardNumberService) CardNumber(cardNumber CardNumber) *MatchNationalCodeWithCardNumberService {
 	s.cardNumber = cardNumber
 	return s
 }
 
+func (s *MatchNationalCodeWithCardNumberService) validated(raw bool) (in MatchNationalCodeWithCardNumberService, err error) {
+	v := &validator{raw: raw}
+	in = *s
+	in.mobileNumber = parseField(v, "mobileNumber", s.mobileNumber, false, ParseMobileNumber)
+	in.nationalCode = parseField(v, "nationalCode", s.nationalCode, true, ParseNationalCode)
+	in.cardNumber = parseField(v, "cardNumber", s.cardNumber, true, ParseCardNumber)
+	return in, v.err()
+}
+
+func (s *MatchNationalCodeWithCardNumberService) Validate() error {
+	_, err := s.validated(false)
+	return err
+}
+
 func (s *MatchNationalCodeWithCardNumberService) Do(ctx context.Context, opts ...RequestOption) (res *MatchNationalCodeWithCardNumber, err error) {
+	in, err := s.validated(!s.c.normalizes(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	r := &request{
 		method:   http.MethodPost,
-		endpoint: "/sanboom/v1/infomatching/cardnationalid",
+		endpoint: EndpointMatchCard,
 		secType:  secTypeAccessToken,
 	}
 
 	r.setJsonParams(params{
-		"mobileNumber": s.mobileNumber,
-		"nationalId":   s.nationalCode,
-		"cardNumber":   s.cardNumber,
+		"mobileNumber": string(in.mobileNumber),
+		"nationalId":   string(in.nationalCode),
+		"cardNumber":   string(in.cardNumber),
 	})
 
 	data, err := s.c.callAPI(ctx, r, opts...)
@@ -101,15 +136,9 @@ func (s *MatchNationalCodeWithCardNumberService) Do(ctx context.Context, opts ..
 	if err != nil {
 		return nil, err
 	}
+	res.RequestTraceId = r.traceId
 
 	return res, nil
 }
 
-type MatchNationalCodeWithCardNumber struct {
-	Error   bool `json:"error"`
-	Message struct {
-		IsMatched bool `json:"ismatched"`
-	} `json:"message"`
-	ResultNumber int    `json:"result_number"`
-	TraceId      string `json:"trace_id"`
-}
+type MatchNationalCodeWithCardNumber = Response[MatchResult]
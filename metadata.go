@@ -0,0 +1,64 @@
+package sanbod
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ResponseMetadata describes the HTTP exchange behind a call. It is filled
+// in by WithMetadata whether the call succeeds or fails. Composite services
+// such as BeneficiaryVerificationService make several calls; the metadata
+// then holds the last one to finish. KYCService keeps it per check instead.
+type ResponseMetadata struct {
+	Method     string        `json:"method"`
+	URL        string        `json:"url"`
+	Endpoint   string        `json:"endpoint"`
+	TraceId    string        `json:"trace_id"`
+	StatusCode int           `json:"status_code"`
+	Header     http.Header   `json:"header"`
+	StartedAt  time.Time     `json:"started_at"`
+	Latency    time.Duration `json:"latency"`
+	// RequestBody is the body that was sent, after normalization.
+	RequestBody string `json:"request_body"`
+	// Coalesced is set when the call shared the response of an identical
+	// request already in flight; the other fields describe that request.
+	Coalesced bool `json:"coalesced"`
+	// CacheHit is set when the response came from Client.ResponseCache; the
+	// traceid is then that of the call that was cached.
+	CacheHit bool `json:"cache_hit"`
+}
+
+// metadataMu serialises writes for the case where one ResponseMetadata is
+// shared by concurrent calls.
+var metadataMu sync.Mutex
+
+func WithMetadata(md *ResponseMetadata) RequestOption {
+	return func(r *request) {
+		r.metadata = md
+	}
+}
+
+func (md *ResponseMetadata) record(r *request, res *http.Response, started time.Time) {
+	if md == nil {
+		return
+	}
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+
+	md.Method = r.method
+	md.URL = r.fullURL
+	md.Endpoint = r.endpoint
+	md.RequestBody = r.sentBody()
+	md.TraceId = r.traceId
+	md.StartedAt = started
+	md.Latency = time.Since(started)
+	md.StatusCode = 0
+	md.Header = nil
+	md.Coalesced = false
+	md.CacheHit = false
+	if res != nil {
+		md.StatusCode = res.StatusCode
+		md.Header = res.Header.Clone()
+	}
+}
@@ -0,0 +1,109 @@
+package sanbod
+
+import (
+	"fmt"
+	"strings"
+)
+
+type MobileOperator string
+
+const (
+	OperatorMCI      MobileOperator = "MCI"
+	OperatorIrancell MobileOperator = "IRANCELL"
+	OperatorRightel  MobileOperator = "RIGHTEL"
+	OperatorShatel   MobileOperator = "SHATEL"
+	OperatorTaliya   MobileOperator = "TALIYA"
+	OperatorSamanTel MobileOperator = "SAMANTEL"
+	OperatorUnknown  MobileOperator = "UNKNOWN"
+)
+
+// MobileNumber holds an Iranian mobile number in the 09XXXXXXXXX form the API expects.
+type MobileNumber string
+
+func ParseMobileNumber(s string) (MobileNumber, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '(' || r == ')' {
+			return -1
+		}
+		return r
+	}, Normalize(s))
+
+	switch {
+	case strings.HasPrefix(s, "+98"):
+		s = "0" + s[3:]
+	case strings.HasPrefix(s, "0098"):
+		s = "0" + s[4:]
+	case strings.HasPrefix(s, "98") && len(s) == 12:
+		s = "0" + s[2:]
+	case strings.HasPrefix(s, "9") && len(s) == 10:
+		s = "0" + s
+	}
+
+	if len(s) != 11 || !isDigits(s) || !strings.HasPrefix(s, "09") {
+		return "", fmt.Errorf("%w: not an Iranian mobile number", ErrInvalidMobileNumber)
+	}
+
+	return MobileNumber(s), nil
+}
+
+func (m MobileNumber) Validate() error {
+	_, err := ParseMobileNumber(string(m))
+	return err
+}
+
+// Operator identifies the network from the number prefix. Numbers ported
+// between operators keep their original prefix, so this is only a hint.
+func (m MobileNumber) Operator() MobileOperator {
+	if len(m) != 11 {
+		return OperatorUnknown
+	}
+	for _, prefix := range []string{string(m[:6]), string(m[:5]), string(m[:4])} {
+		if op, ok := mobilePrefixes[prefix]; ok {
+			return op
+		}
+	}
+	return OperatorUnknown
+}
+
+func (m MobileNumber) String() string {
+	return string(m)
+}
+
+var mobilePrefixes = map[string]MobileOperator{
+	"0910":  OperatorMCI,
+	"0911":  OperatorMCI,
+	"0912":  OperatorMCI,
+	"0913":  OperatorMCI,
+	"0914":  OperatorMCI,
+	"0915":  OperatorMCI,
+	"0916":  OperatorMCI,
+	"0917":  OperatorMCI,
+	"0918":  OperatorMCI,
+	"0919":  OperatorMCI,
+	"0990":  OperatorMCI,
+	"0991":  OperatorMCI,
+	"0992":  OperatorMCI,
+	"0993":  OperatorMCI,
+	"0994":  OperatorMCI,
+	"0900":  OperatorIrancell,
+	"0901":  OperatorIrancell,
+	"0902":  OperatorIrancell,
+	"0903":  OperatorIrancell,
+	"0904":  OperatorIrancell,
+	"0905":  OperatorIrancell,
+	"0930":  OperatorIrancell,
+	"0933":  OperatorIrancell,
+	"0935":  OperatorIrancell,
+	"0936":  OperatorIrancell,
+	"0937":  OperatorIrancell,
+	"0938":  OperatorIrancell,
+	"0939":  OperatorIrancell,
+	"0941":  OperatorIrancell,
+	"0920":  OperatorRightel,
+	"0921":  OperatorRightel,
+	"0922":  OperatorRightel,
+	"0923":  OperatorRightel,
+	"0932":  OperatorTaliya,
+	"09981": OperatorShatel,
+	"09999": OperatorSamanTel,
+}
@@ -0,0 +1,44 @@
+package sanbod
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMobileNumber(t *testing.T) {
+	tests := []struct {
+		in           string
+		want         MobileNumber
+		wantOperator MobileOperator
+	}{
+		{"09121234567", "09121234567", OperatorMCI},
+		{"+989121234567", "09121234567", OperatorMCI},
+		{"00989351234567", "09351234567", OperatorIrancell},
+		{"989201234567", "09201234567", OperatorRightel},
+		{"9321234567", "09321234567", OperatorTaliya},
+		{"0912 123-4567", "09121234567", OperatorMCI},
+		{"+98 (912) 123 4567", "09121234567", OperatorMCI},
+		{"۰۹۹۸۱۲۳۴۵۶۷", "09981234567", OperatorShatel},
+		{"٠٩٩٩٩١٢٣٤٥٦", "09999123456", OperatorSamanTel},
+		{"09991234567", "09991234567", OperatorUnknown},
+	}
+	for _, tt := range tests {
+		got, err := ParseMobileNumber(tt.in)
+		if err != nil {
+			t.Errorf("ParseMobileNumber(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || got.Operator() != tt.wantOperator {
+			t.Errorf("ParseMobileNumber(%q) = %q (%s), want %q (%s)", tt.in, got, got.Operator(), tt.want, tt.wantOperator)
+		}
+	}
+
+	for _, in := range []string{"", "0912123456", "091212345678", "02112345678", "+442071234567", "0912abc4567", "98912123456"} {
+		if _, err := ParseMobileNumber(in); !errors.Is(err, ErrInvalidMobileNumber) {
+			t.Errorf("ParseMobileNumber(%q) error = %v, want ErrInvalidMobileNumber", in, err)
+		}
+	}
+	if MobileNumber("0912").Operator() != OperatorUnknown {
+		t.Error("short number should have no operator")
+	}
+}
@@ -0,0 +1,241 @@
+// Package namematch compares Persian personal names as they come back from
+// banks and the civil registry with what users type, and explains the score.
+//
+// Word order is ignored: رضایی علی scores 1 against علی رضایی, with
+// ReasonWordOrder, because banks and the registry disagree on whether the
+// last name comes first. Callers that must tell first and last names apart
+// should compare them separately. A name that is only an honorific, such as
+// سید, is kept rather than stripped to nothing, and empty or whitespace-only
+// names score 0 against anything.
+package namematch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Reason string
+
+const (
+	// ReasonExact means the names are identical once trimmed.
+	ReasonExact Reason = "exact"
+	// ReasonArabicLetters means Arabic ي, ى, ك or ة had to be folded to Persian.
+	ReasonArabicLetters Reason = "arabic_letters"
+	// ReasonDiacritics means harakat, tatweel or hamza forms were dropped.
+	ReasonDiacritics Reason = "diacritics"
+	// ReasonSpacing means the names differ only in spaces or ZWNJ, as in محمد علی and محمدعلی.
+	ReasonSpacing Reason = "spacing"
+	// ReasonHonorific means a title such as سید or حاج was removed from one side.
+	ReasonHonorific Reason = "honorific"
+	// ReasonWordOrder means the words match in a different order.
+	ReasonWordOrder Reason = "word_order"
+	// ReasonEditDistance means letters still differ and the score is below 1.
+	ReasonEditDistance Reason = "edit_distance"
+)
+
+var DefaultHonorifics = []string{
+	"سید", "سیده", "حاج", "حاجی", "حاجیه", "شیخ", "آقای", "آقا", "خانم", "دکتر", "مهندس",
+}
+
+type Result struct {
+	Score   float64  `json:"score"`
+	Reasons []Reason `json:"reasons"`
+	// A and B are the names after normalization and honorific removal.
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+func (r Result) Has(reason Reason) bool {
+	for _, v := range r.Reasons {
+		if v == reason {
+			return true
+		}
+	}
+	return false
+}
+
+type Matcher struct {
+	Honorifics []string
+}
+
+func New() *Matcher {
+	return &Matcher{Honorifics: DefaultHonorifics}
+}
+
+var defaultMatcher = New()
+
+func Compare(a, b string) Result {
+	return defaultMatcher.Compare(a, b)
+}
+
+// Normalize folds Arabic letters to Persian, drops diacritics and tatweel,
+// turns ZWNJ into a space and collapses whitespace.
+func Normalize(s string) string {
+	s, _, _ = normalize(s)
+	return s
+}
+
+func (m *Matcher) Compare(a, b string) Result {
+	res := Result{}
+	if strings.TrimSpace(a) == strings.TrimSpace(b) && strings.TrimSpace(a) != "" {
+		res.Score = 1
+		res.A, res.B = strings.TrimSpace(a), strings.TrimSpace(b)
+		res.Reasons = []Reason{ReasonExact}
+		return res
+	}
+
+	na, arabicA, diacriticA := normalize(a)
+	nb, arabicB, diacriticB := normalize(b)
+	if arabicA || arabicB {
+		res.Reasons = append(res.Reasons, ReasonArabicLetters)
+	}
+	if diacriticA || diacriticB {
+		res.Reasons = append(res.Reasons, ReasonDiacritics)
+	}
+
+	wa, wb := strings.Fields(na), strings.Fields(nb)
+	if strings.Join(wa, "") != strings.Join(wb, "") {
+		sa, strippedA := m.stripHonorifics(wa)
+		sb, strippedB := m.stripHonorifics(wb)
+		if strippedA || strippedB {
+			res.Reasons = append(res.Reasons, ReasonHonorific)
+			wa, wb = sa, sb
+		}
+	}
+	res.A, res.B = strings.Join(wa, " "), strings.Join(wb, " ")
+
+	ca, cb := strings.Join(wa, ""), strings.Join(wb, "")
+	if ca != "" && ca == cb && res.A != res.B {
+		res.Reasons = append(res.Reasons, ReasonSpacing)
+	}
+
+	res.Score = similarity(ca, cb)
+	if res.Score < 1 {
+		sorted := similarity(sortedJoin(wa), sortedJoin(wb))
+		if sorted > res.Score {
+			res.Score = sorted
+			res.Reasons = append(res.Reasons, ReasonWordOrder)
+		}
+	}
+	if res.Score < 1 {
+		res.Reasons = append(res.Reasons, ReasonEditDistance)
+	}
+	return res
+}
+
+func (m *Matcher) stripHonorifics(words []string) (out []string, stripped bool) {
+	out = make([]string, 0, len(words))
+	for i, w := range words {
+		if m.isHonorific(w) && len(words) > 1 {
+			stripped = true
+			continue
+		}
+		// سید is often written joined to the given name, سیدمحمد.
+		if i == 0 {
+			for _, h := range []string{"سیده", "سید"} {
+				if m.isHonorific(h) && strings.HasPrefix(w, h) && utf8.RuneCountInString(w)-utf8.RuneCountInString(h) >= 3 {
+					w = strings.TrimPrefix(w, h)
+					stripped = true
+					break
+				}
+			}
+		}
+		out = append(out, w)
+	}
+	return out, stripped
+}
+
+func (m *Matcher) isHonorific(w string) bool {
+	for _, h := range m.Honorifics {
+		if w == h {
+			return true
+		}
+	}
+	return false
+}
+
+var arabicLetters = map[rune]rune{
+	'ي': 'ی',
+	'ى': 'ی',
+	'ئ': 'ی',
+	'ك': 'ک',
+	'ة': 'ه',
+	'ۀ': 'ه',
+}
+
+var hamzaForms = map[rune]rune{
+	'أ': 'ا',
+	'إ': 'ا',
+	'ٱ': 'ا',
+	'ؤ': 'و',
+}
+
+func normalize(s string) (out string, arabic, diacritic bool) {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '۰' && r <= '۹':
+			r = '0' + (r - '۰')
+		case r >= '٠' && r <= '٩':
+			r = '0' + (r - '٠')
+		case r == '\u200c' || r == '\u00a0':
+			r = ' '
+		case r == '\u0640' || (r >= '\u064b' && r <= '\u065f') || r == '\u0670':
+			diacritic = true
+			continue
+		case unicode.Is(unicode.Cf, r):
+			continue
+		}
+		if v, ok := arabicLetters[r]; ok {
+			arabic = true
+			r = v
+		}
+		if v, ok := hamzaForms[r]; ok {
+			diacritic = true
+			r = v
+		}
+		b.WriteRune(r)
+	}
+	return strings.Join(strings.Fields(b.String()), " "), arabic, diacritic
+}
+
+func sortedJoin(words []string) string {
+	w := append([]string(nil), words...)
+	sort.Strings(w)
+	return strings.Join(w, "")
+}
+
+// similarity is one minus the edit distance over the longer length.
+func similarity(a, b string) float64 {
+	if a == b {
+		if a == "" {
+			return 0
+		}
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,86 @@
+package namematch
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		minScore float64
+		reason   Reason
+	}{
+		{"علی رضایی", "علی رضایی", 1, ReasonExact},
+		{"علي رضايي", "علی رضایی", 1, ReasonArabicLetters},
+		{"محمد علی", "محمدعلی", 1, ReasonSpacing},
+		{"محمد‌علی", "محمد علی", 1, ""},
+		{"مُحَمَّد", "محمد", 1, ReasonDiacritics},
+		{"سید محمد موسوی", "محمد موسوی", 1, ReasonHonorific},
+		{"سیدمحمد موسوی", "محمد موسوی", 1, ReasonHonorific},
+		{"رضایی علی", "علی رضایی", 1, ReasonWordOrder},
+		{"علی رضائی", "علی رضایی", 0.85, ReasonArabicLetters},
+	}
+	for _, tt := range tests {
+		res := Compare(tt.a, tt.b)
+		if res.Score < tt.minScore {
+			t.Errorf("Compare(%q, %q) score %v, want at least %v", tt.a, tt.b, res.Score, tt.minScore)
+		}
+		if tt.reason != "" && !res.Has(tt.reason) {
+			t.Errorf("Compare(%q, %q) reasons %v, want %s", tt.a, tt.b, res.Reasons, tt.reason)
+		}
+	}
+}
+
+func TestCompareDifferentNames(t *testing.T) {
+	res := Compare("زهرا احمدی", "علی رضایی")
+	if res.Score > 0.5 || !res.Has(ReasonEditDistance) {
+		t.Fatalf("unexpected result %+v", res)
+	}
+}
+
+func TestCompareSwappedNames(t *testing.T) {
+	res := Compare("رضایی علی", "علی رضایی")
+	if res.Score != 1 || !res.Has(ReasonWordOrder) || res.Has(ReasonEditDistance) {
+		t.Fatalf("swapped first and last names should score 1 by word order, got %+v", res)
+	}
+}
+
+func TestCompareHonorificOnly(t *testing.T) {
+	if res := Compare("سید", "سید"); res.Score != 1 || res.A != "سید" {
+		t.Fatalf("a lone honorific should still be a name, got %+v", res)
+	}
+	if res := Compare("سید", "محمد"); res.Score >= 0.5 || res.Has(ReasonHonorific) {
+		t.Fatalf("a lone honorific should not be stripped to match anything, got %+v", res)
+	}
+	if res := Compare("سید محمد", "سید"); res.Score >= 0.5 {
+		t.Fatalf("an honorific alone should not match a full name, got %+v", res)
+	}
+}
+
+func TestCompareEmpty(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"", ""},
+		{" ", "\t"},
+		{"\u200c", " "},
+		{"", "علی رضایی"},
+		{"  ", "علی رضایی"},
+	}
+	for _, tt := range tests {
+		if res := Compare(tt.a, tt.b); res.Score != 0 || res.Has(ReasonExact) {
+			t.Errorf("Compare(%q, %q) = %+v, want a score of 0", tt.a, tt.b, res)
+		}
+	}
+}
+
+func TestMatcherCustomHonorifics(t *testing.T) {
+	m := &Matcher{Honorifics: []string{"استاد"}}
+	if res := m.Compare("استاد علی رضایی", "علی رضایی"); res.Score != 1 || !res.Has(ReasonHonorific) {
+		t.Fatalf("custom honorific should be stripped, got %+v", res)
+	}
+	if res := m.Compare("سید علی رضایی", "علی رضایی"); res.Score == 1 || res.Has(ReasonHonorific) {
+		t.Fatalf("default honorifics should not apply to a custom list, got %+v", res)
+	}
+	if res := New().Compare("استاد علی رضایی", "علی رضایی"); res.Score == 1 {
+		t.Fatalf("the default matcher should not know custom honorifics, got %+v", res)
+	}
+}
@@ -0,0 +1,57 @@
+package sanbod
+
+import (
+	"fmt"
+	"strings"
+)
+
+type NationalCode string
+
+func ParseNationalCode(s string) (NationalCode, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, Normalize(s))
+
+	if len(s) == 8 || len(s) == 9 {
+		s = strings.Repeat("0", 10-len(s)) + s
+	}
+	if len(s) != 10 || !isDigits(s) {
+		return "", fmt.Errorf("%w: must be 10 digits", ErrInvalidNationalCode)
+	}
+	if strings.Count(s, s[:1]) == len(s) {
+		return "", fmt.Errorf("%w: repeated digits", ErrInvalidNationalCode)
+	}
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(s[i]-'0') * (10 - i)
+	}
+	check := int(s[9] - '0')
+	r := sum % 11
+	if (r < 2 && check != r) || (r >= 2 && check != 11-r) {
+		return "", fmt.Errorf("%w: %w", ErrInvalidNationalCode, ErrInvalidChecksum)
+	}
+
+	return NationalCode(s), nil
+}
+
+func (n NationalCode) Validate() error {
+	_, err := ParseNationalCode(string(n))
+	return err
+}
+
+func (n NationalCode) String() string {
+	return string(n)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,39 @@
+package sanbod
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNationalCode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    NationalCode
+		wantErr error
+	}{
+		{"0499370899", "0499370899", nil},
+		{"1234567891", "1234567891", nil},
+		{"049-937089 9", "0499370899", nil},
+		{"۰۴۹۹۳۷۰۸۹۹", "0499370899", nil},
+		{"123456789", "0123456789", nil},
+		{"12345679", "0012345679", nil},
+		{"84575948", "0084575948", nil},
+		{"1234567", "", ErrInvalidNationalCode},
+		{"12345678901", "", ErrInvalidNationalCode},
+		{"04993708a9", "", ErrInvalidNationalCode},
+		{"", "", ErrInvalidNationalCode},
+		{"1111111111", "", ErrInvalidNationalCode},
+		{"0000000000", "", ErrInvalidNationalCode},
+		{"0499370898", "", ErrInvalidChecksum},
+		{"12345678", "", ErrInvalidChecksum},
+	}
+	for _, tt := range tests {
+		got, err := ParseNationalCode(tt.in)
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+			t.Errorf("ParseNationalCode(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseNationalCode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package sanbod
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Normalize converts Persian and Arabic-Indic digits to ASCII, drops
+// zero-width and bidi control characters and trims surrounding whitespace.
+// Services run it over their typed inputs and every query, form and JSON
+// parameter before sending unless Client.DisableNormalization or
+// WithoutNormalization is set.
+func Normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		case isInvisible(r):
+			return -1
+		case r == '\u00a0':
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// isInvisible reports zero-width joiners, bidi marks and other format
+// characters that form inputs tend to carry along with copied text.
+func isInvisible(r rune) bool {
+	return unicode.Is(unicode.Cf, r)
+}
+
+func normalizeParams(m params) params {
+	out := make(params, len(m))
+	for k, v := range m {
+		switch v := v.(type) {
+		case string:
+			out[k] = Normalize(v)
+		case []string:
+			n := make([]string, len(v))
+			for i := range v {
+				n[i] = Normalize(v[i])
+			}
+			out[k] = n
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func normalizeValues(v url.Values) url.Values {
+	if v == nil {
+		return nil
+	}
+	out := make(url.Values, len(v))
+	for k, vs := range v {
+		n := make([]string, len(vs))
+		for i := range vs {
+			n[i] = Normalize(vs[i])
+		}
+		out[k] = n
+	}
+	return out
+}
+
+// normalizes reports whether a call made with opts runs its inputs through
+// Normalize.
+func (c *Client) normalizes(opts []RequestOption) bool {
+	if c.DisableNormalization {
+		return false
+	}
+	r := new(request)
+	for _, opt := range opts {
+		opt(r)
+	}
+	return !r.rawParams
+}
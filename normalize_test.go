@@ -0,0 +1,97 @@
+package sanbod
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"۰۹۱۲۳۴۵۶۷۸۹", "09123456789"},
+		{"٠١٢٣٤٥٦٧٨٩", "0123456789"},
+		{"\u200f0499\u200c370899\u200e", "0499370899"},
+		{"\ufeff 6219 8612 \u00a0", "6219 8612"},
+		{"علی\u00a0رضا", "علی رضا"},
+		{"IR82 0540", "IR82 0540"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseRequestNormalization(t *testing.T) {
+	newRequest := func() *request {
+		r := &request{
+			query: url.Values{"q": {"۱۲۳ "}},
+			form:  url.Values{"f": {"\u200c۴۵۶"}},
+		}
+		return r.setJsonParams(params{"s": " ۷۸۹", "l": []string{"۱", "\u200e۲"}, "n": 7})
+	}
+
+	c := NewClient("username", "password")
+	r := newRequest()
+	err := c.parseRequest(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.query.Get("q") != "123" || r.form.Get("f") != "456" || r.sentBody() != `{"l":["1","2"],"n":7,"s":"789"}` {
+		t.Fatalf("unexpected request query %v form %v body %s", r.query, r.form, r.sentBody())
+	}
+
+	r = newRequest()
+	err = c.parseRequest(context.Background(), r, WithoutNormalization())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.query.Get("q") != "۱۲۳ " || r.form.Get("f") != "\u200c۴۵۶" || r.jsonParams["s"] != " ۷۸۹" {
+		t.Fatalf("WithoutNormalization changed the request: query %v form %v json %v", r.query, r.form, r.jsonParams)
+	}
+
+	c.DisableNormalization = true
+	r = newRequest()
+	err = c.parseRequest(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.query.Get("q") != "۱۲۳ " || r.form.Get("f") != "\u200c۴۵۶" || r.jsonParams["s"] != " ۷۸۹" {
+		t.Fatalf("DisableNormalization changed the request: query %v form %v json %v", r.query, r.form, r.jsonParams)
+	}
+}
+
+func TestNormalizationOfTypedInputs(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointCardToIban: `{"error":false,"message":{"iban":"IR820540102680020817909002"}}`,
+	})
+	card := CardNumber("۶۲۱۹-۸۶۱۲-۳۴۵۶-۷۸۹۸")
+
+	var md ResponseMetadata
+	_, err := c.NewCardToIbanService().CardNumber(card).Do(context.Background(), WithMetadata(&md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.bodies[EndpointCardToIban]["cardNumber"] != "6219861234567898" || md.RequestBody != `{"cardNumber":"6219861234567898"}` {
+		t.Fatalf("expected the normalized card number, got %v and metadata body %s", f.bodies[EndpointCardToIban], md.RequestBody)
+	}
+
+	_, err = c.NewCardToIbanService().CardNumber(card).Do(context.Background(), WithoutNormalization(), WithMetadata(&md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.bodies[EndpointCardToIban]["cardNumber"] != string(card) {
+		t.Fatalf("expected the card number as given, got %v", f.bodies[EndpointCardToIban])
+	}
+	if md.RequestBody != `{"cardNumber":"`+string(card)+`"}` {
+		t.Fatalf("unexpected metadata body %s", md.RequestBody)
+	}
+
+	_, err = c.NewCardToIbanService().CardNumber("۶۲۱۹-۸۶۱۲-۳۴۵۶-۷۸۹۹").Do(context.Background(), WithoutNormalization())
+	if !IsValidationError(err) {
+		t.Fatalf("inputs should still be validated without normalization, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package sanbod
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is consulted by the client before every request.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+}
+
+// NewRateLimiter allows rps requests per second on average with bursts of up to burst requests.
+// An rps of zero or less means no limit.
+func NewRateLimiter(rps float64, burst int) RateLimiter {
+	if rps <= 0 {
+		return unlimited{}
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:     rps,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	for {
+		delay := b.reserve()
+		if delay == 0 {
+			return nil
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long
+// to wait for the next one.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.lastFill).Seconds()*b.rate)
+	b.lastFill = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+type unlimited struct{}
+
+func (unlimited) Wait(ctx context.Context) error {
+	return nil
+}
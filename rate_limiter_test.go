@@ -0,0 +1,54 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(20, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 4 {
+		err := l.Wait(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the burst of two is free, the next two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond || elapsed > time.Second {
+		t.Fatalf("four requests at 20 rps with a burst of 2 took %s", elapsed)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	err := l.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = l.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to end with the context, got %v", err)
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	for _, rps := range []float64{0, -1} {
+		l := NewRateLimiter(rps, 0)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		for range 1000 {
+			err := l.Wait(ctx)
+			if err != nil {
+				t.Fatalf("rps %v should not limit, got %v", rps, err)
+			}
+		}
+		cancel()
+	}
+}
@@ -53,11 +53,16 @@ type request struct {
 	query      url.Values
 	form       url.Values
 	json       []byte
+	jsonParams params
+	rawParams  bool
 	secType    secType
 	recvWindow int64
 	header     http.Header
 	body       io.Reader
 	fullURL    string
+	traceId    string
+	metadata   *ResponseMetadata
+	cacheMode  cacheMode
 }
 
 func (r *request) addParam(key string, value interface{}) *request {
@@ -109,10 +114,18 @@ func (r *request) setFormParams(m params) *request {
 }
 
 func (r *request) setJsonParams(m params) *request {
-	r.json, _ = json.Marshal(m)
+	r.jsonParams = m
 	return r
 }
 
+// sentBody returns the body as it goes on the wire.
+func (r *request) sentBody() string {
+	if r.json != nil {
+		return string(r.json)
+	}
+	return r.form.Encode()
+}
+
 func (r *request) validate() (err error) {
 	if r.query == nil {
 		r.query = url.Values{}
@@ -131,6 +144,13 @@ func WithRecvWindow(recvWindow int64) RequestOption {
 	}
 }
 
+// WithoutNormalization sends parameters exactly as given, skipping Normalize.
+func WithoutNormalization() RequestOption {
+	return func(r *request) {
+		r.rawParams = true
+	}
+}
+
 func WithHeader(key, value string, replace bool) RequestOption {
 	return func(r *request) {
 		if r.header == nil {
@@ -0,0 +1,28 @@
+package sanbod
+
+import "net/http"
+
+// Response is the envelope every sanboom endpoint wraps its result in.
+// RequestTraceId is the traceid the client sent, TraceId the one returned.
+type Response[T any] struct {
+	Error          bool   `json:"error"`
+	Message        T      `json:"message"`
+	ResultNumber   int    `json:"result_number"`
+	TraceId        string `json:"trace_id"`
+	RequestTraceId string `json:"-"`
+}
+
+// Err returns the envelope as an *APIError when the api answered 200 but
+// flagged the result as an error, and nil otherwise.
+func (r *Response[T]) Err() error {
+	if r == nil || !r.Error {
+		return nil
+	}
+	return &APIError{
+		Err:            true,
+		ResultNumber:   int64(r.ResultNumber),
+		TraceId:        r.TraceId,
+		StatusCode:     http.StatusOK,
+		RequestTraceId: r.RequestTraceId,
+	}
+}
@@ -0,0 +1,204 @@
+package sanbod
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CacheBackend stores responses for ResponseCache. Keys are hex HMAC-SHA256
+// hashes of the request and the client's Username, never the inputs
+// themselves.
+type CacheBackend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// ResponseCache keeps responses of the endpoints listed in TTL, such as
+// EndpointInquiryProfile, so repeated inquiries are not billed again.
+type ResponseCache struct {
+	Backend CacheBackend
+	// TTL is how long responses of each endpoint are kept. Endpoints not
+	// listed are never cached.
+	TTL map[string]time.Duration
+	// NotFoundTTL is how long a 404 answer is kept, not at all when zero.
+	NotFoundTTL time.Duration
+	// KeySecret is the HMAC key of the cache keys. Processes that share a
+	// Backend must set the same secret to share entries; when empty, each
+	// client uses a random key and sees only its own entries.
+	KeySecret []byte
+}
+
+type cacheMode int
+
+const (
+	cacheDefault cacheMode = iota
+	cacheBypass
+	cacheRefresh
+)
+
+// WithCacheBypass neither reads nor writes the response cache for this call.
+func WithCacheBypass() RequestOption {
+	return func(r *request) {
+		r.cacheMode = cacheBypass
+	}
+}
+
+// WithCacheRefresh skips the cached response for this call and stores the fresh one.
+func WithCacheRefresh() RequestOption {
+	return func(r *request) {
+		r.cacheMode = cacheRefresh
+	}
+}
+
+type cacheEntry struct {
+	StatusCode int    `json:"status_code"`
+	Body       []byte `json:"body"`
+	TraceId    string `json:"trace_id"`
+}
+
+// fetch answers r from the response cache when it can, otherwise sends it
+// and caches the answer.
+func (c *Client) fetch(ctx context.Context, r *request) ([]byte, error) {
+	rc := c.ResponseCache
+	if rc == nil || rc.Backend == nil || rc.TTL[r.endpoint] <= 0 || r.cacheMode == cacheBypass {
+		return c.send(ctx, r)
+	}
+
+	secret := rc.KeySecret
+	if len(secret) == 0 {
+		secret = c.keySecret
+	}
+	key := requestKey(secret, c.Username, r)
+	if r.cacheMode != cacheRefresh {
+		value, ok, err := rc.Backend.Get(ctx, key)
+		if err != nil {
+			c.debug("response cache get failed: %s", err)
+		}
+		var entry cacheEntry
+		if ok && json.Unmarshal(value, &entry) == nil {
+			return c.cacheHit(r, entry)
+		}
+	}
+
+	data, err := c.send(ctx, r)
+
+	entry, ttl := cacheEntry{StatusCode: http.StatusOK, Body: data, TraceId: r.traceId}, rc.TTL[r.endpoint]
+	if err == nil {
+		// a 200 can still carry "error": true, which must not be replayed
+		var envelope struct {
+			Error bool `json:"error"`
+		}
+		if json.Unmarshal(data, &envelope) != nil || envelope.Error {
+			return data, nil
+		}
+	} else {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || rc.NotFoundTTL <= 0 {
+			return data, err
+		}
+		entry.StatusCode, ttl = apiErr.StatusCode, rc.NotFoundTTL
+		entry.Body, _ = json.Marshal(apiErr)
+	}
+	value, e := json.Marshal(entry)
+	if e == nil {
+		e = rc.Backend.Set(ctx, key, value, ttl)
+	}
+	if e != nil {
+		c.debug("response cache set failed: %s", e)
+	}
+	return data, err
+}
+
+func (c *Client) cacheHit(r *request, entry cacheEntry) ([]byte, error) {
+	r.traceId = entry.TraceId
+	if r.metadata != nil {
+		metadataMu.Lock()
+		*r.metadata = ResponseMetadata{
+			Method:      r.method,
+			URL:         r.fullURL,
+			Endpoint:    r.endpoint,
+			RequestBody: r.sentBody(),
+			TraceId:     entry.TraceId,
+			StatusCode:  entry.StatusCode,
+			StartedAt:   time.Now(),
+			CacheHit:    true,
+		}
+		metadataMu.Unlock()
+	}
+	c.debug("traceid: %s, response served from cache", entry.TraceId)
+
+	if entry.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: entry.StatusCode, RequestTraceId: entry.TraceId}
+		_ = json.Unmarshal(entry.Body, apiErr)
+		return nil, apiErr
+	}
+	return entry.Body, nil
+}
+
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns an in-memory CacheBackend holding at most size
+// entries, dropping the least recently used first.
+func NewLRUCache(size int) CacheBackend {
+	if size < 1 {
+		size = 1
+	}
+	return &lruCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (l *lruCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := e.Value.(*lruItem)
+	if time.Now().After(item.expires) {
+		l.ll.Remove(e)
+		delete(l.items, key)
+		return nil, false, nil
+	}
+	l.ll.MoveToFront(e)
+	return item.value, true, nil
+}
+
+func (l *lruCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	if e, ok := l.items[key]; ok {
+		item := e.Value.(*lruItem)
+		item.value, item.expires = value, expires
+		l.ll.MoveToFront(e)
+		return nil
+	}
+	l.items[key] = l.ll.PushFront(&lruItem{key: key, value: value, expires: expires})
+	for l.ll.Len() > l.size {
+		e := l.ll.Back()
+		l.ll.Remove(e)
+		delete(l.items, e.Value.(*lruItem).key)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointIbanInquiry: `{"error":false,"message":{"iban":"IR820540102680020817909002","depositStatus":"02"}}`,
+	})
+	c.ResponseCache = &ResponseCache{
+		Backend: NewLRUCache(10),
+		TTL: map[string]time.Duration{
+			EndpointIbanInquiry: time.Hour,
+			EndpointCardToIban:  time.Hour,
+		},
+		NotFoundTTL: time.Minute,
+	}
+
+	inquire := func(opts ...RequestOption) (*IbanInquiry, ResponseMetadata) {
+		t.Helper()
+		var md ResponseMetadata
+		res, err := c.NewIbanInquiryService().
+			Iban("IR82 0540 1026 8002 0817 9090 02").
+			Do(context.Background(), append(opts, WithMetadata(&md))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res, md
+	}
+
+	first, md := inquire()
+	if md.CacheHit {
+		t.Fatal("first call should miss the cache")
+	}
+	second, md := inquire()
+	if !md.CacheHit || md.StatusCode != http.StatusOK || second.RequestTraceId != first.RequestTraceId || !second.Message.DepositStatus.IsActive() {
+		t.Fatalf("expected cache hit with the original trace id, got %+v and %+v", md, second)
+	}
+	if f.calls[EndpointIbanInquiry] != 1 {
+		t.Fatalf("expected one upstream call, got %d", f.calls[EndpointIbanInquiry])
+	}
+
+	_, md = inquire(WithCacheBypass())
+	if md.CacheHit || f.calls[EndpointIbanInquiry] != 2 {
+		t.Fatalf("bypass should call upstream, got %+v", md)
+	}
+	refreshed, _ := inquire(WithCacheRefresh())
+	_, md = inquire()
+	if f.calls[EndpointIbanInquiry] != 3 || !md.CacheHit || md.TraceId != refreshed.RequestTraceId {
+		t.Fatalf("refresh should call upstream and replace the entry, got %+v", md)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.NewCardToIbanService().CardNumber("6219861234567898").Do(context.Background())
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.ResultNumber != 404 {
+			t.Fatalf("expected cached not found, got %v", err)
+		}
+	}
+	if f.calls[EndpointCardToIban] != 1 {
+		t.Fatalf("expected not found to be cached, got %d calls", f.calls[EndpointCardToIban])
+	}
+}
+
+func TestResponseCacheSkipsErrorEnvelopes(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointInquiryProfile: `{"error":true,"message":{},"result_number":-1}`,
+	})
+	c.ResponseCache = &ResponseCache{
+		Backend: NewLRUCache(10),
+		TTL:     map[string]time.Duration{EndpointInquiryProfile: time.Hour},
+	}
+
+	for i := 0; i < 2; i++ {
+		var md ResponseMetadata
+		res, err := c.NewInquiryUserProfileService().
+			NationalCode("0499370899").
+			Birthdate(JalaliDate{1370, 5, 12}).
+			Do(context.Background(), WithMetadata(&md))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Error || md.CacheHit {
+			t.Fatalf("expected the error envelope from upstream, got %+v and %+v", res, md)
+		}
+	}
+	if f.calls[EndpointInquiryProfile] != 2 {
+		t.Fatalf("error envelopes should not be cached, got %d calls", f.calls[EndpointInquiryProfile])
+	}
+}
+
+func TestResponseCacheKeys(t *testing.T) {
+	f, _ := newFakeServer(t, map[string]string{
+		EndpointCardToIban: `{"error":false,"message":{"iban":"IR820540102680020817909002"}}`,
+	})
+	backend := NewLRUCache(10)
+	client := func(username string, secret []byte) *Client {
+		c := NewClient(username, "password")
+		c.SetApiEndpoint(f.URL)
+		c.cache.set(CacheAccessToken, "token")
+		c.ResponseCache = &ResponseCache{
+			Backend:   backend,
+			TTL:       map[string]time.Duration{EndpointCardToIban: time.Hour},
+			KeySecret: secret,
+		}
+		return c
+	}
+	cacheHit := func(c *Client) bool {
+		t.Helper()
+		var md ResponseMetadata
+		_, err := c.NewCardToIbanService().CardNumber("6219861234567898").Do(context.Background(), WithMetadata(&md))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return md.CacheHit
+	}
+
+	secret := []byte("shared secret")
+	if cacheHit(client("username", secret)) || !cacheHit(client("username", secret)) {
+		t.Fatal("clients with the same secret and username should share entries")
+	}
+	if cacheHit(client("other", secret)) {
+		t.Fatal("entries should not be shared across usernames")
+	}
+	if cacheHit(client("username", []byte("another secret"))) {
+		t.Fatal("entries should not be shared across secrets")
+	}
+	if cacheHit(client("username", nil)) || cacheHit(client("username", nil)) {
+		t.Fatal("clients without a secret should not share entries")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	l := NewLRUCache(2)
+	_ = l.Set(ctx, "a", []byte("1"), time.Hour)
+	_ = l.Set(ctx, "b", []byte("2"), time.Hour)
+	_, _, _ = l.Get(ctx, "a")
+	_ = l.Set(ctx, "c", []byte("3"), time.Hour)
+	_ = l.Set(ctx, "d", []byte("4"), -time.Second)
+
+	if _, ok, _ := l.Get(ctx, "b"); ok {
+		t.Fatal("least recently used entry should be evicted")
+	}
+	if v, ok, _ := l.Get(ctx, "c"); !ok || string(v) != "3" {
+		t.Fatal("expected c to be cached")
+	}
+	if _, ok, _ := l.Get(ctx, "d"); ok {
+		t.Fatal("expired entry should not be returned")
+	}
+}
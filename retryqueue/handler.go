@@ -0,0 +1,158 @@
+package retryqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/parparvaz/sanbod-sdk-golang"
+)
+
+// Handler replays a job's payload against the api and returns its response.
+type Handler func(ctx context.Context, api sanbod.API, payload []byte) (any, error)
+
+// Kinds of the built-in handlers, one per sanbod.API method. The payload of
+// each is the method's request value, such as a sanbod.ProfileRequest for
+// KindInquireProfile or a sanbod.IBAN for KindInquireIban.
+const (
+	KindInquireProfile          = "inquire_profile"
+	KindInquireProfileWithImage = "inquire_profile_with_image"
+	KindVerifyCitizenship       = "verify_citizenship"
+	KindMatchMobile             = "match_mobile"
+	KindMatchCard               = "match_card"
+	KindCardToDeposit           = "card_to_deposit"
+	KindCardToIban              = "card_to_iban"
+	KindDepositToIban           = "deposit_to_iban"
+	KindIbanToDeposit           = "iban_to_deposit"
+	KindInquireIban             = "inquire_iban"
+	KindRunKYC                  = "run_kyc"
+	KindVerifyBeneficiary       = "verify_beneficiary"
+)
+
+func defaultHandlers() map[string]Handler {
+	return map[string]Handler{
+		KindInquireProfile:          handler(sanbod.API.InquireProfile),
+		KindInquireProfileWithImage: handler(sanbod.API.InquireProfileWithImage),
+		KindVerifyCitizenship:       handler(sanbod.API.VerifyCitizenship),
+		KindMatchMobile:             handler(sanbod.API.MatchMobile),
+		KindMatchCard:               handler(sanbod.API.MatchCard),
+		KindCardToDeposit:           handler(sanbod.API.CardToDeposit),
+		KindCardToIban:              handler(sanbod.API.CardToIban),
+		KindDepositToIban:           handler(sanbod.API.DepositToIban),
+		KindIbanToDeposit:           handler(sanbod.API.IbanToDeposit),
+		KindInquireIban:             handler(sanbod.API.InquireIban),
+		KindRunKYC:                  runKYC,
+		KindVerifyBeneficiary:       handler(sanbod.API.VerifyBeneficiary),
+	}
+}
+
+func handler[Req, Res any](method func(sanbod.API, context.Context, Req, ...sanbod.RequestOption) (Res, error)) Handler {
+	return func(ctx context.Context, api sanbod.API, payload []byte) (any, error) {
+		var req Req
+		err := json.Unmarshal(payload, &req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBadPayload, err)
+		}
+		return method(api, ctx, req)
+	}
+}
+
+// partialError is returned by a handler whose job got part of the way
+// before failing. The job is retried with payload, and res is reported to
+// OnComplete if it is dead-lettered instead.
+type partialError struct {
+	err     error
+	payload json.RawMessage
+	res     any
+}
+
+func (e *partialError) Error() string { return e.err.Error() }
+
+func (e *partialError) Unwrap() error { return e.err }
+
+// kycJob is the payload of KindRunKYC: the request and, once a run has
+// been tried, its report, so that a retry repeats only the checks that
+// errored and the ones already paid for are kept.
+type kycJob struct {
+	sanbod.KYCRequest
+	Report *sanbod.KYCReport `json:"report,omitempty"`
+}
+
+// runKYC replays a KYC run, which reports failed calls per check instead of
+// as its error. A run with checks that failed on a retryable error is
+// retried for those checks only; one with a check that failed otherwise is
+// dead-lettered with the report so far.
+func runKYC(ctx context.Context, api sanbod.API, payload []byte) (any, error) {
+	var job kycJob
+	err := json.Unmarshal(payload, &job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadPayload, err)
+	}
+	req := job.KYCRequest
+	if job.Report != nil {
+		req.Checks = nil
+		for _, c := range job.Report.Checks {
+			if c.Status == sanbod.KYCStatusError {
+				req.Checks = append(req.Checks, c.Check)
+			}
+		}
+	}
+
+	report, err := api.RunKYC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, nil
+	}
+	if job.Report != nil {
+		mergeKYC(job.Report, report)
+		report = job.Report
+	}
+
+	var retry error
+	for _, c := range report.Checks {
+		if c.Status != sanbod.KYCStatusError || c.Err == nil {
+			continue
+		}
+		err = fmt.Errorf("kyc check %s: %w", c.Check, c.Err)
+		if !sanbod.IsRetryable(c.Err) {
+			retry = err
+			break
+		}
+		if retry == nil {
+			retry = err
+		}
+	}
+	if retry == nil {
+		return report, nil
+	}
+	payload, err = json.Marshal(kycJob{KYCRequest: job.KYCRequest, Report: report})
+	if err != nil {
+		return nil, err
+	}
+	return nil, &partialError{err: retry, payload: payload, res: report}
+}
+
+// mergeKYC replaces the checks of report that rerun repeated, along with
+// their responses.
+func mergeKYC(report, rerun *sanbod.KYCReport) {
+	for _, c := range rerun.Checks {
+		for i := range report.Checks {
+			if report.Checks[i].Check == c.Check {
+				report.Checks[i] = c
+			}
+		}
+		switch c.Check {
+		case sanbod.KYCCheckProfile:
+			report.Profile = rerun.Profile
+		case sanbod.KYCCheckMobileMatch:
+			report.MobileMatch = rerun.MobileMatch
+		case sanbod.KYCCheckCardMatch:
+			report.CardMatch = rerun.CardMatch
+		case sanbod.KYCCheckCardIban:
+			report.CardIban = rerun.CardIban
+		}
+	}
+	report.Duration += rerun.Duration
+}
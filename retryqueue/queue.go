@@ -0,0 +1,335 @@
+// Package retryqueue keeps sanbod inquiries that failed during an outage on
+// disk and replays them with backoff until they succeed or run out of
+// attempts. Jobs are encrypted with AES-GCM, survive restarts, and end up in
+// a dead-letter directory when they cannot be completed. Files that cannot
+// be decrypted, for example after a key change, are moved to a quarantine
+// directory when the queue is opened.
+//
+//	res, err := client.InquireProfile(ctx, req)
+//	if sanbod.IsRetryable(err) {
+//		_, err = queue.Enqueue(retryqueue.KindInquireProfile, req)
+//	}
+package retryqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parparvaz/sanbod-sdk-golang"
+)
+
+const (
+	defaultMaxAttempts  = 10
+	defaultMinBackoff   = 10 * time.Second
+	defaultMaxBackoff   = 30 * time.Minute
+	defaultPollInterval = time.Second
+)
+
+var (
+	ErrUnknownKind = errors.New("retryqueue: unknown job kind")
+	// ErrBadPayload is returned by the built-in handlers when a payload
+	// does not decode into the request of the job's kind. Such jobs are
+	// dead-lettered without being retried.
+	ErrBadPayload = errors.New("retryqueue: job payload does not match its kind")
+)
+
+type Job struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+	NextRunAt time.Time       `json:"next_run_at"`
+	LastError string          `json:"last_error,omitempty"`
+}
+
+// Result is passed to Config.OnComplete once a job is finished. Err is nil
+// when the job succeeded; otherwise the job was moved to the dead letters
+// and Response holds whatever the job got done before failing, such as the
+// report of a KYC run with the checks that did complete.
+type Result struct {
+	Job      Job
+	Response any
+	Err      error
+}
+
+type Config struct {
+	// Dir holds the pending, dead and quarantined job files.
+	Dir string
+	// Key encrypts the job files; 16, 24 or 32 bytes for AES-128, 192 or 256.
+	Key []byte
+	API sanbod.API
+	// MaxAttempts is how many times a job runs before it is dead-lettered,
+	// 10 when zero.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the wait between attempts, which
+	// doubles after each failure; 10s and 30m when zero.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// PollInterval is how often Run looks for due jobs, 1s when zero.
+	PollInterval time.Duration
+	OnComplete   func(Result)
+	// OnQuarantine, when set, is told about each job file Open moves aside
+	// because it cannot be read.
+	OnQuarantine func(id string, err error)
+	// Handlers adds or replaces handlers by kind on top of the built-in ones.
+	Handlers map[string]Handler
+}
+
+type Queue struct {
+	cfg      Config
+	store    *store
+	handlers map[string]Handler
+	wake     chan struct{}
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// Open loads the pending jobs in cfg.Dir, creating it when needed. Job files
+// that cannot be decrypted or decoded are moved to the quarantine directory.
+func Open(cfg Config) (*Queue, error) {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaultMinBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+
+	s, err := newStore(cfg.Dir, cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	q := &Queue{
+		cfg:      cfg,
+		store:    s,
+		handlers: defaultHandlers(),
+		wake:     make(chan struct{}, 1),
+		jobs:     make(map[string]*Job),
+	}
+	for kind, h := range cfg.Handlers {
+		q.handlers[kind] = h
+	}
+
+	ids, err := s.list(pendingDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		job, err := s.load(pendingDir, id)
+		if errors.Is(err, ErrCorruptJob) {
+			e := s.quarantine(pendingDir, id)
+			if e != nil {
+				return nil, e
+			}
+			if cfg.OnQuarantine != nil {
+				cfg.OnQuarantine(id, err)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("retryqueue: loading job %s: %w", id, err)
+		}
+		q.jobs[id] = job
+	}
+	return q, nil
+}
+
+// Enqueue stores req, the request value of the handler for kind, to be run
+// by Run as soon as possible.
+func (q *Queue) Enqueue(kind string, req any) (string, error) {
+	if _, ok := q.handlers[kind]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Payload:   payload,
+		CreatedAt: now,
+		NextRunAt: now,
+	}
+	err = q.store.save(pendingDir, job)
+	if err != nil {
+		return "", err
+	}
+
+	q.mu.Lock()
+	q.jobs[job.ID] = job
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job.ID, nil
+}
+
+// Len returns the number of pending jobs.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.jobs)
+}
+
+// DeadLetters returns the jobs that ran out of attempts or failed for good.
+func (q *Queue) DeadLetters() ([]Job, error) {
+	ids, err := q.store.list(deadDir)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := q.store.load(deadDir, id)
+		if err != nil {
+			return nil, fmt.Errorf("retryqueue: loading job %s: %w", id, err)
+		}
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// Run processes due jobs one at a time until ctx is done. Only one Run may
+// use a directory at a time.
+func (q *Queue) Run(ctx context.Context) error {
+	for {
+		err := q.runDue(ctx)
+		if err != nil {
+			return err
+		}
+
+		t := time.NewTimer(q.cfg.PollInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-q.wake:
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
+
+func (q *Queue) runDue(ctx context.Context) error {
+	now := time.Now()
+	q.mu.Lock()
+	var due []*Job
+	for _, job := range q.jobs {
+		if !job.NextRunAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	q.mu.Unlock()
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextRunAt.Before(due[j].NextRunAt)
+	})
+
+	for _, job := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err := q.attempt(ctx, job)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attempt runs job once and records the outcome. The returned error is
+// only for failures of the queue itself.
+func (q *Queue) attempt(ctx context.Context, job *Job) error {
+	h, ok := q.handlers[job.Kind]
+	if !ok {
+		return q.finish(job, nil, fmt.Errorf("%w: %s", ErrUnknownKind, job.Kind))
+	}
+
+	res, err := h(ctx, q.cfg.API, job.Payload)
+	if err == nil {
+		return q.finish(job, res, nil)
+	}
+	if errors.Is(err, ErrBadPayload) {
+		job.Attempts++
+		return q.finish(job, nil, err)
+	}
+	// the progress of a partial run is kept so that it is not repeated
+	var partial *partialError
+	if errors.As(err, &partial) {
+		res, job.Payload = partial.res, partial.payload
+	} else {
+		res = nil
+	}
+	// stopping the queue is not a failure of the job
+	if ctx.Err() != nil {
+		if partial != nil {
+			return q.store.save(pendingDir, job)
+		}
+		return nil
+	}
+
+	job.Attempts++
+	job.LastError = err.Error()
+	if !sanbod.IsRetryable(err) || job.Attempts >= q.cfg.MaxAttempts {
+		return q.finish(job, res, err)
+	}
+	job.NextRunAt = time.Now().Add(q.backoff(job.Attempts))
+	return q.store.save(pendingDir, job)
+}
+
+// finish removes job from the pending ones, dead-lettering it when err is
+// set, and reports it to OnComplete.
+func (q *Queue) finish(job *Job, res any, err error) error {
+	if err != nil {
+		job.LastError = err.Error()
+		e := q.store.save(deadDir, job)
+		if e != nil {
+			return e
+		}
+	}
+	e := q.store.remove(pendingDir, job.ID)
+	if e != nil {
+		return e
+	}
+
+	q.mu.Lock()
+	delete(q.jobs, job.ID)
+	q.mu.Unlock()
+
+	if q.cfg.OnComplete != nil {
+		q.cfg.OnComplete(Result{Job: *job, Response: res, Err: err})
+	}
+	return nil
+}
+
+// backoff doubles from MinBackoff with each attempt, capped at MaxBackoff,
+// and picks a random point in the upper half to spread retries out.
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := q.cfg.MinBackoff
+	for i := 1; i < attempts && d < q.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, q.cfg.MaxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,304 @@
+package retryqueue
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/parparvaz/sanbod-sdk-golang"
+	"github.com/parparvaz/sanbod-sdk-golang/sanbodmock"
+)
+
+var testKey = bytes.Repeat([]byte{7}, 32)
+
+func TestQueue(t *testing.T) {
+	dir := t.TempDir()
+	var calls atomic.Int32
+	api := &sanbodmock.API{
+		InquireProfileFunc: func(ctx context.Context, req sanbod.ProfileRequest) (*sanbod.InquiryUserProfile, error) {
+			if calls.Add(1) < 3 {
+				return nil, &sanbod.APIError{StatusCode: http.StatusServiceUnavailable}
+			}
+			res := new(sanbod.InquiryUserProfile)
+			res.Message.NationalId = string(req.NationalCode)
+			return res, nil
+		},
+		InquireIbanFunc: func(ctx context.Context, iban sanbod.IBAN) (*sanbod.IbanInquiry, error) {
+			return nil, &sanbod.APIError{StatusCode: http.StatusNotFound}
+		},
+	}
+	cfg := Config{Dir: dir, Key: testKey, API: api, MinBackoff: time.Millisecond, PollInterval: time.Millisecond}
+
+	q, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	birthDate, _ := sanbod.NewJalaliDate(1370, 1, 1)
+	_, err = q.Enqueue(KindInquireProfile, sanbod.ProfileRequest{NationalCode: "0499370899", BirthDate: birthDate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.Enqueue(KindInquireIban, sanbod.IBAN("IR820540102680020817909002"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = q.Enqueue("unknown", nil); !errors.Is(err, ErrUnknownKind) {
+		t.Fatalf("expected ErrUnknownKind, got %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, pendingDir, "*"+jobExt))
+	for _, name := range files {
+		data, _ := os.ReadFile(name)
+		if bytes.Contains(data, []byte("0499370899")) || bytes.Contains(data, []byte("IR82")) {
+			t.Fatal("job stored in the clear")
+		}
+	}
+
+	// a restarted process picks the jobs up again
+	q, err = Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 pending jobs after reopen, got %d", q.Len())
+	}
+
+	results := make(chan Result, 2)
+	cfg.OnComplete = func(r Result) { results <- r }
+	q, err = Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() { _ = q.Run(ctx) }()
+
+	got := map[string]Result{}
+	for len(got) < 2 {
+		select {
+		case r := <-results:
+			got[r.Job.Kind] = r
+		case <-ctx.Done():
+			t.Fatal("jobs did not complete")
+		}
+	}
+	profile := got[KindInquireProfile]
+	if profile.Err != nil || profile.Job.Attempts != 2 || profile.Response.(*sanbod.InquiryUserProfile).Message.NationalId != "0499370899" {
+		t.Fatalf("unexpected profile result %+v", profile)
+	}
+	if got[KindInquireIban].Err == nil {
+		t.Fatal("expected the iban job to fail for good")
+	}
+
+	dead, err := q.DeadLetters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || dead[0].Kind != KindInquireIban || dead[0].LastError == "" || q.Len() != 0 {
+		t.Fatalf("unexpected dead letters %+v", dead)
+	}
+
+	cfg.Key = bytes.Repeat([]byte{8}, 32)
+	q, err = Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = q.DeadLetters(); !errors.Is(err, ErrCorruptJob) {
+		t.Fatalf("expected ErrCorruptJob with the wrong key, got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	q := &Queue{cfg: Config{MinBackoff: time.Second, MaxBackoff: time.Minute}}
+	for attempts, want := range map[int]time.Duration{1: time.Second, 3: 4 * time.Second, 100: time.Minute} {
+		d := q.backoff(attempts)
+		if d < want/2 || d > want {
+			t.Fatalf("backoff(%d) = %s, want between %s and %s", attempts, d, want/2, want)
+		}
+	}
+}
+
+// runJobs runs q until n jobs complete and returns their results by kind.
+func runJobs(t *testing.T, cfg Config, n int, enqueue func(q *Queue)) map[string]Result {
+	t.Helper()
+
+	results := make(chan Result, n)
+	cfg.OnComplete = func(r Result) { results <- r }
+	q, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enqueue(q)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() { _ = q.Run(ctx) }()
+
+	got := map[string]Result{}
+	for len(got) < n {
+		select {
+		case r := <-results:
+			got[r.Job.Kind] = r
+		case <-ctx.Done():
+			t.Fatal("jobs did not complete")
+		}
+	}
+	return got
+}
+
+func TestQueueKYCCheckErrors(t *testing.T) {
+	// fakeKYC runs the requested checks, all of them when none are given,
+	// counting the calls made for each
+	var mu sync.Mutex
+	calls := map[sanbod.KYCCheck]int{}
+	fakeKYC := func(outcome func(check sanbod.KYCCheck, call int) error) func(context.Context, sanbod.KYCRequest) (*sanbod.KYCReport, error) {
+		return func(ctx context.Context, req sanbod.KYCRequest) (*sanbod.KYCReport, error) {
+			checks := req.Checks
+			if len(checks) == 0 {
+				checks = []sanbod.KYCCheck{sanbod.KYCCheckProfile, sanbod.KYCCheckCardMatch, sanbod.KYCCheckCardIban}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report := new(sanbod.KYCReport)
+			for _, check := range checks {
+				calls[check]++
+				res := sanbod.KYCCheckResult{Check: check, Status: sanbod.KYCStatusPassed}
+				if err := outcome(check, calls[check]); err != nil {
+					res.Status, res.Err = sanbod.KYCStatusError, err
+				}
+				report.Checks = append(report.Checks, res)
+				if check == sanbod.KYCCheckCardIban && res.Err == nil {
+					report.CardIban = new(sanbod.CardToIban)
+					report.CardIban.Message.Iban = "IR820540102680020817909002"
+				}
+			}
+			return report, nil
+		}
+	}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	req := sanbod.KYCRequest{NationalCode: "0499370899", BirthDate: sanbod.JalaliDate{Year: 1370, Month: 5, Day: 12}, CardNumber: "6219861234567898"}
+
+	api := &sanbodmock.API{RunKYCFunc: fakeKYC(func(check sanbod.KYCCheck, call int) error {
+		if check == sanbod.KYCCheckCardIban && call < 3 {
+			return refused
+		}
+		return nil
+	})}
+	cfg := Config{Dir: t.TempDir(), Key: testKey, API: api, MinBackoff: time.Millisecond, PollInterval: time.Millisecond}
+
+	got := runJobs(t, cfg, 1, func(q *Queue) {
+		_, err := q.Enqueue(KindRunKYC, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	kyc := got[KindRunKYC]
+	report, _ := kyc.Response.(*sanbod.KYCReport)
+	if kyc.Err != nil || kyc.Job.Attempts != 2 || report == nil || !report.Passed() || len(report.Checks) != 3 || report.CardIban == nil {
+		t.Fatalf("expected the run to be retried after a transport error, got %+v", kyc)
+	}
+	want := map[sanbod.KYCCheck]int{sanbod.KYCCheckProfile: 1, sanbod.KYCCheckCardMatch: 1, sanbod.KYCCheckCardIban: 3}
+	if !maps.Equal(calls, want) {
+		t.Fatalf("expected only the failed check to be repeated, got calls %v", calls)
+	}
+
+	clear(calls)
+	api.RunKYCFunc = fakeKYC(func(check sanbod.KYCCheck, call int) error {
+		switch {
+		case check == sanbod.KYCCheckCardIban && call == 1:
+			return refused
+		case check == sanbod.KYCCheckCardIban:
+			return &sanbod.APIError{StatusCode: http.StatusNotFound}
+		}
+		return nil
+	})
+	cfg.Dir = t.TempDir()
+	got = runJobs(t, cfg, 1, func(q *Queue) {
+		_, err := q.Enqueue(KindRunKYC, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	kyc = got[KindRunKYC]
+	report, _ = kyc.Response.(*sanbod.KYCReport)
+	if !sanbod.IsAPIError(kyc.Err) || kyc.Job.Attempts != 2 || report == nil {
+		t.Fatalf("expected a final check error to dead-letter the run with its report, got %+v", kyc)
+	}
+	if check, _ := report.Check(sanbod.KYCCheckProfile); check.Status != sanbod.KYCStatusPassed {
+		t.Fatalf("expected the paid for checks in the dead-lettered report, got %+v", report.Checks)
+	}
+	want = map[sanbod.KYCCheck]int{sanbod.KYCCheckProfile: 1, sanbod.KYCCheckCardMatch: 1, sanbod.KYCCheckCardIban: 2}
+	if !maps.Equal(calls, want) {
+		t.Fatalf("expected only the failed check to be repeated, got calls %v", calls)
+	}
+}
+
+func TestQueueBadPayload(t *testing.T) {
+	api := &sanbodmock.API{}
+	cfg := Config{Dir: t.TempDir(), Key: testKey, API: api, MinBackoff: time.Millisecond, PollInterval: time.Millisecond}
+
+	got := runJobs(t, cfg, 1, func(q *Queue) {
+		_, err := q.Enqueue(KindInquireIban, map[string]int{"iban": 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	res := got[KindInquireIban]
+	if !errors.Is(res.Err, ErrBadPayload) || res.Job.Attempts != 1 || len(api.Calls()) != 0 {
+		t.Fatalf("expected the job to be dead-lettered at once, got %+v", res)
+	}
+}
+
+func TestOpenQuarantinesCorruptJobs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{Dir: dir, Key: testKey, API: &sanbodmock.API{}}
+	q, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	good, err := q.Enqueue(KindInquireIban, sanbod.IBAN("IR820540102680020817909002"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, err := q.Enqueue(KindCardToIban, sanbod.CardNumber("6219861234567898"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, pendingDir, bad+jobExt), []byte("garbage"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quarantined := map[string]error{}
+	cfg.OnQuarantine = func(id string, err error) { quarantined[id] = err }
+	q, err = Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 1 || len(quarantined) != 1 || !errors.Is(quarantined[bad], ErrCorruptJob) {
+		t.Fatalf("expected only %s to be quarantined, got %v with %d pending", bad, quarantined, q.Len())
+	}
+	if _, err = os.Stat(filepath.Join(dir, quarantineDir, bad+jobExt)); err != nil {
+		t.Fatalf("expected the file in quarantine: %v", err)
+	}
+
+	// with the wrong key nothing can be read, but the queue still opens
+	cfg.Key = bytes.Repeat([]byte{8}, 32)
+	clear(quarantined)
+	q, err = Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 0 || len(quarantined) != 1 || quarantined[good] == nil {
+		t.Fatalf("expected %s to be quarantined, got %v", good, quarantined)
+	}
+}
@@ -0,0 +1,156 @@
+package retryqueue
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	pendingDir    = "pending"
+	deadDir       = "dead"
+	quarantineDir = "quarantine"
+	jobExt        = ".job"
+	tmpExt        = ".tmp"
+)
+
+var ErrCorruptJob = errors.New("retryqueue: job file cannot be decrypted")
+
+// store keeps one encrypted file per job, pending or dead. Files are
+// replaced atomically, so a crash leaves either the old or the new version.
+type store struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+func newStore(dir string, key []byte) (*store, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range []string{pendingDir, deadDir, quarantineDir} {
+		err = os.MkdirAll(filepath.Join(dir, sub), 0700)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &store{dir: dir, aead: aead}, nil
+}
+
+func (s *store) path(sub, id string) string {
+	return filepath.Join(s.dir, sub, id+jobExt)
+}
+
+func (s *store) save(sub string, job *Job) error {
+	plain, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+	// the id is bound as additional data so a file cannot be swapped for another job's
+	data := s.aead.Seal(nonce, nonce, plain, []byte(job.ID))
+
+	path := s.path(sub, job.ID)
+	tmp := path + tmpExt
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (s *store) load(sub, id string) (*Job, error) {
+	data, err := os.ReadFile(s.path(sub, id))
+	if err != nil {
+		return nil, err
+	}
+	n := s.aead.NonceSize()
+	if len(data) < n {
+		return nil, ErrCorruptJob
+	}
+	plain, err := s.aead.Open(nil, data[:n], data[n:], []byte(id))
+	if err != nil {
+		return nil, ErrCorruptJob
+	}
+	job := new(Job)
+	err = json.Unmarshal(plain, job)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptJob, err)
+	}
+	return job, nil
+}
+
+// quarantine moves the file of id out of sub, keeping it as it is for
+// inspection or for a later restore.
+func (s *store) quarantine(sub, id string) error {
+	err := os.Rename(s.path(sub, id), s.path(quarantineDir, id))
+	if err != nil {
+		return err
+	}
+	return syncDir(filepath.Join(s.dir, sub))
+}
+
+func (s *store) remove(sub, id string) error {
+	err := os.Remove(s.path(sub, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// list returns the ids in sub, removing temporary files left by a crash.
+func (s *store) list(sub string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, sub))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasSuffix(name, tmpExt):
+			_ = os.Remove(filepath.Join(s.dir, sub, name))
+		case strings.HasSuffix(name, jobExt):
+			ids = append(ids, strings.TrimSuffix(name, jobExt))
+		}
+	}
+	return ids, nil
+}
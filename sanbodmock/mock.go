@@ -0,0 +1,136 @@
+// Package sanbodmock provides hand-written fakes of the sanbod interfaces.
+// Set the Func fields a test needs; calling a method whose Func is nil
+// returns ErrNotMocked.
+package sanbodmock
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/parparvaz/sanbod-sdk-golang"
+)
+
+var ErrNotMocked = errors.New("sanbodmock: method not mocked")
+
+// Call records a method invocation on API.
+type Call struct {
+	Method  string
+	Request any
+}
+
+type API struct {
+	InquireProfileFunc          func(ctx context.Context, req sanbod.ProfileRequest) (*sanbod.InquiryUserProfile, error)
+	InquireProfileWithImageFunc func(ctx context.Context, req sanbod.ProfileRequest) (*sanbod.InquiryUserProfileWithImage, error)
+	VerifyCitizenshipFunc       func(ctx context.Context, req sanbod.ProfileRequest) (*sanbod.CitizenshipVerification, error)
+	MatchMobileFunc             func(ctx context.Context, req sanbod.MobileMatchRequest) (*sanbod.MatchNationalCodeWithMobileNumber, error)
+	MatchCardFunc               func(ctx context.Context, req sanbod.CardMatchRequest) (*sanbod.MatchNationalCodeWithCardNumber, error)
+	CardToDepositFunc           func(ctx context.Context, cardNumber sanbod.CardNumber) (*sanbod.CardToAccountNumber, error)
+	CardToIbanFunc              func(ctx context.Context, cardNumber sanbod.CardNumber) (*sanbod.CardToIban, error)
+	DepositToIbanFunc           func(ctx context.Context, req sanbod.DepositToIbanRequest) (*sanbod.AccountNumberToIban, error)
+	IbanToDepositFunc           func(ctx context.Context, iban sanbod.IBAN) (*sanbod.IbanToAccountNumber, error)
+	InquireIbanFunc             func(ctx context.Context, iban sanbod.IBAN) (*sanbod.IbanInquiry, error)
+	RunKYCFunc                  func(ctx context.Context, req sanbod.KYCRequest) (*sanbod.KYCReport, error)
+	VerifyBeneficiaryFunc       func(ctx context.Context, req sanbod.BeneficiaryRequest) (*sanbod.BeneficiaryVerification, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var (
+	_ sanbod.API                    = (*API)(nil)
+	_ sanbod.InquiryUserProfileDoer = (*Doer[*sanbod.InquiryUserProfile])(nil)
+)
+
+// Calls returns the invocations made so far, in order.
+func (m *API) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *API) record(method string, req any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Request: req})
+}
+
+func call[Req, Res any](m *API, method string, f func(context.Context, Req) (Res, error), ctx context.Context, req Req) (Res, error) {
+	m.record(method, req)
+	if f == nil {
+		var zero Res
+		return zero, ErrNotMocked
+	}
+	return f(ctx, req)
+}
+
+func (m *API) InquireProfile(ctx context.Context, req sanbod.ProfileRequest, _ ...sanbod.RequestOption) (*sanbod.InquiryUserProfile, error) {
+	return call(m, "InquireProfile", m.InquireProfileFunc, ctx, req)
+}
+
+func (m *API) InquireProfileWithImage(ctx context.Context, req sanbod.ProfileRequest, _ ...sanbod.RequestOption) (*sanbod.InquiryUserProfileWithImage, error) {
+	return call(m, "InquireProfileWithImage", m.InquireProfileWithImageFunc, ctx, req)
+}
+
+func (m *API) VerifyCitizenship(ctx context.Context, req sanbod.ProfileRequest, _ ...sanbod.RequestOption) (*sanbod.CitizenshipVerification, error) {
+	return call(m, "VerifyCitizenship", m.VerifyCitizenshipFunc, ctx, req)
+}
+
+func (m *API) MatchMobile(ctx context.Context, req sanbod.MobileMatchRequest, _ ...sanbod.RequestOption) (*sanbod.MatchNationalCodeWithMobileNumber, error) {
+	return call(m, "MatchMobile", m.MatchMobileFunc, ctx, req)
+}
+
+func (m *API) MatchCard(ctx context.Context, req sanbod.CardMatchRequest, _ ...sanbod.RequestOption) (*sanbod.MatchNationalCodeWithCardNumber, error) {
+	return call(m, "MatchCard", m.MatchCardFunc, ctx, req)
+}
+
+func (m *API) CardToDeposit(ctx context.Context, cardNumber sanbod.CardNumber, _ ...sanbod.RequestOption) (*sanbod.CardToAccountNumber, error) {
+	return call(m, "CardToDeposit", m.CardToDepositFunc, ctx, cardNumber)
+}
+
+func (m *API) CardToIban(ctx context.Context, cardNumber sanbod.CardNumber, _ ...sanbod.RequestOption) (*sanbod.CardToIban, error) {
+	return call(m, "CardToIban", m.CardToIbanFunc, ctx, cardNumber)
+}
+
+func (m *API) DepositToIban(ctx context.Context, req sanbod.DepositToIbanRequest, _ ...sanbod.RequestOption) (*sanbod.AccountNumberToIban, error) {
+	return call(m, "DepositToIban", m.DepositToIbanFunc, ctx, req)
+}
+
+func (m *API) IbanToDeposit(ctx context.Context, iban sanbod.IBAN, _ ...sanbod.RequestOption) (*sanbod.IbanToAccountNumber, error) {
+	return call(m, "IbanToDeposit", m.IbanToDepositFunc, ctx, iban)
+}
+
+func (m *API) InquireIban(ctx context.Context, iban sanbod.IBAN, _ ...sanbod.RequestOption) (*sanbod.IbanInquiry, error) {
+	return call(m, "InquireIban", m.InquireIbanFunc, ctx, iban)
+}
+
+func (m *API) RunKYC(ctx context.Context, req sanbod.KYCRequest, _ ...sanbod.RequestOption) (*sanbod.KYCReport, error) {
+	return call(m, "RunKYC", m.RunKYCFunc, ctx, req)
+}
+
+func (m *API) VerifyBeneficiary(ctx context.Context, req sanbod.BeneficiaryRequest, _ ...sanbod.RequestOption) (*sanbod.BeneficiaryVerification, error) {
+	return call(m, "VerifyBeneficiary", m.VerifyBeneficiaryFunc, ctx, req)
+}
+
+// Doer fakes any single service, such as sanbod.InquiryUserProfileDoer.
+type Doer[T any] struct {
+	ValidateFunc func() error
+	DoFunc       func(ctx context.Context, opts ...sanbod.RequestOption) (T, error)
+}
+
+func (d *Doer[T]) Validate() error {
+	if d.ValidateFunc == nil {
+		return nil
+	}
+	return d.ValidateFunc()
+}
+
+func (d *Doer[T]) Do(ctx context.Context, opts ...sanbod.RequestOption) (T, error) {
+	if d.DoFunc == nil {
+		var zero T
+		return zero, ErrNotMocked
+	}
+	return d.DoFunc(ctx, opts...)
+}
@@ -0,0 +1,118 @@
+package sanbodmock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/parparvaz/sanbod-sdk-golang"
+)
+
+func TestAPIRecordsCalls(t *testing.T) {
+	req := sanbod.ProfileRequest{NationalCode: "0499370899", BirthDate: sanbod.JalaliDate{Year: 1370, Month: 5, Day: 12}}
+	api := &API{
+		InquireProfileFunc: func(ctx context.Context, got sanbod.ProfileRequest) (*sanbod.InquiryUserProfile, error) {
+			if got != req {
+				t.Errorf("InquireProfileFunc got %+v, want %+v", got, req)
+			}
+			res := new(sanbod.InquiryUserProfile)
+			res.Message.FirstName = "علی"
+			return res, nil
+		},
+	}
+
+	res, err := api.InquireProfile(context.Background(), req)
+	if err != nil || res.Message.FirstName != "علی" {
+		t.Fatalf("unexpected result %+v, %v", res, err)
+	}
+	_, err = api.InquireIban(context.Background(), "IR820540102680020817909002")
+	if !errors.Is(err, ErrNotMocked) {
+		t.Fatalf("expected ErrNotMocked, got %v", err)
+	}
+
+	want := []Call{
+		{Method: "InquireProfile", Request: req},
+		{Method: "InquireIban", Request: sanbod.IBAN("IR820540102680020817909002")},
+	}
+	if got := api.Calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Calls() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAPINotMocked(t *testing.T) {
+	api := &API{}
+	ctx := context.Background()
+
+	errs := map[string]error{}
+	_, errs["InquireProfile"] = api.InquireProfile(ctx, sanbod.ProfileRequest{})
+	_, errs["InquireProfileWithImage"] = api.InquireProfileWithImage(ctx, sanbod.ProfileRequest{})
+	_, errs["VerifyCitizenship"] = api.VerifyCitizenship(ctx, sanbod.ProfileRequest{})
+	_, errs["MatchMobile"] = api.MatchMobile(ctx, sanbod.MobileMatchRequest{})
+	_, errs["MatchCard"] = api.MatchCard(ctx, sanbod.CardMatchRequest{})
+	_, errs["CardToDeposit"] = api.CardToDeposit(ctx, "")
+	_, errs["CardToIban"] = api.CardToIban(ctx, "")
+	_, errs["DepositToIban"] = api.DepositToIban(ctx, sanbod.DepositToIbanRequest{})
+	_, errs["IbanToDeposit"] = api.IbanToDeposit(ctx, "")
+	_, errs["InquireIban"] = api.InquireIban(ctx, "")
+	_, errs["RunKYC"] = api.RunKYC(ctx, sanbod.KYCRequest{})
+	_, errs["VerifyBeneficiary"] = api.VerifyBeneficiary(ctx, sanbod.BeneficiaryRequest{})
+
+	for method, err := range errs {
+		if !errors.Is(err, ErrNotMocked) {
+			t.Errorf("%s: expected ErrNotMocked, got %v", method, err)
+		}
+	}
+	if len(api.Calls()) != len(errs) {
+		t.Fatalf("expected %d recorded calls, got %d", len(errs), len(api.Calls()))
+	}
+}
+
+func TestAPIConcurrentCalls(t *testing.T) {
+	api := &API{
+		CardToIbanFunc: func(ctx context.Context, cardNumber sanbod.CardNumber) (*sanbod.CardToIban, error) {
+			return new(sanbod.CardToIban), nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = api.CardToIban(context.Background(), "6219861234567898")
+		}()
+	}
+	wg.Wait()
+	if len(api.Calls()) != 50 {
+		t.Fatalf("expected 50 recorded calls, got %d", len(api.Calls()))
+	}
+}
+
+func TestDoer(t *testing.T) {
+	var d sanbod.InquiryUserProfileDoer = &Doer[*sanbod.InquiryUserProfile]{}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("unset Validate should pass, got %v", err)
+	}
+	if _, err := d.Do(context.Background()); !errors.Is(err, ErrNotMocked) {
+		t.Fatalf("expected ErrNotMocked, got %v", err)
+	}
+
+	invalid := errors.New("invalid")
+	d = &Doer[*sanbod.InquiryUserProfile]{
+		ValidateFunc: func() error { return invalid },
+		DoFunc: func(ctx context.Context, opts ...sanbod.RequestOption) (*sanbod.InquiryUserProfile, error) {
+			if len(opts) != 1 {
+				t.Errorf("expected the options to be passed on, got %d", len(opts))
+			}
+			return new(sanbod.InquiryUserProfile), nil
+		},
+	}
+	if err := d.Validate(); err != invalid {
+		t.Fatalf("expected the Validate error, got %v", err)
+	}
+	if res, err := d.Do(context.Background(), sanbod.WithoutNormalization()); res == nil || err != nil {
+		t.Fatalf("unexpected result %v, %v", res, err)
+	}
+}
@@ -0,0 +1,59 @@
+package sanbod
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type traceIdKey struct{}
+
+// ContextWithTraceId makes calls made with ctx send id as their traceid,
+// unless WithTraceId overrides it for a single call.
+func ContextWithTraceId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIdKey{}, id)
+}
+
+func TraceIdFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(traceIdKey{}).(string)
+	return id, ok && id != ""
+}
+
+func WithTraceId(id string) RequestOption {
+	return func(r *request) {
+		r.traceId = id
+	}
+}
+
+// traceId picks the id for r: the request option first, then the context,
+// then Client.TraceIdGenerator and finally a random UUID.
+func (c *Client) traceId(ctx context.Context, r *request) string {
+	if r.traceId != "" {
+		return r.traceId
+	}
+	if ctx != nil {
+		if id, ok := TraceIdFromContext(ctx); ok {
+			return id
+		}
+	}
+	if c.TraceIdGenerator != nil {
+		if id := c.TraceIdGenerator(); id != "" {
+			return id
+		}
+	}
+	return uuid.New().String()
+}
+
+// callerTraceId returns the traceid the caller set for a composite call with
+// WithTraceId or the context, if any, so each call it makes can derive its own.
+func callerTraceId(ctx context.Context, opts []RequestOption) string {
+	r := new(request)
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.traceId != "" {
+		return r.traceId
+	}
+	id, _ := TraceIdFromContext(ctx)
+	return id
+}
@@ -0,0 +1,129 @@
+package sanbod
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	ReasonMissing  = "missing"
+	ReasonFormat   = "format"
+	ReasonChecksum = "checksum"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Err     error  `json:"-"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned by Validate, and by Do before any request is
+// sent, listing every invalid input of the service.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "sanbod: validation failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		errs[i] = f
+	}
+	return errs
+}
+
+// StatusCode is the HTTP status a handler should answer with when passing
+// the error on to its own callers.
+func (e *ValidationError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+func IsValidationError(e error) bool {
+	var validationError *ValidationError
+	ok := errors.As(e, &validationError)
+	return ok
+}
+
+type validator struct {
+	fields []FieldError
+	// raw keeps inputs as given once they are known to parse, for calls
+	// made without normalization.
+	raw bool
+}
+
+func (v *validator) missing(field string) {
+	v.fields = append(v.fields, FieldError{
+		Field:   field,
+		Reason:  ReasonMissing,
+		Message: "is required",
+		Err:     ErrMissingField,
+	})
+}
+
+func (v *validator) invalid(field string, err error) {
+	reason := ReasonFormat
+	if errors.Is(err, ErrInvalidChecksum) {
+		reason = ReasonChecksum
+	}
+	v.fields = append(v.fields, FieldError{
+		Field:   field,
+		Reason:  reason,
+		Message: err.Error(),
+		Err:     err,
+	})
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// parseField returns the parsed form of value, or value itself when v is raw,
+// recording a field error when it is empty and required or when it does not
+// parse. The caller's value is left as it is.
+func parseField[T ~string](v *validator, field string, value T, required bool, parse func(string) (T, error)) T {
+	if value == "" {
+		if required {
+			v.missing(field)
+		}
+		return value
+	}
+	parsed, err := parse(string(value))
+	if err != nil {
+		v.invalid(field, err)
+		return value
+	}
+	if v.raw {
+		return value
+	}
+	return parsed
+}
+
+func (v *validator) jalaliDate(field string, value JalaliDate) {
+	if value.IsZero() {
+		v.missing(field)
+		return
+	}
+	if err := value.Validate(); err != nil {
+		v.invalid(field, err)
+	}
+}
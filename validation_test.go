@@ -0,0 +1,70 @@
+package sanbod
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestValidationErrorListsEveryField(t *testing.T) {
+	f, c := newFakeServer(t, nil)
+
+	_, err := c.NewBeneficiaryVerificationService().
+		Iban("IR16 0120 0000 0000 1234 5678 91").
+		NationalCode("0499370898").
+		Threshold(1.5).
+		Do(context.Background())
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	want := []struct{ field, reason string }{
+		{"iban", ReasonChecksum},
+		{"nationalCode", ReasonChecksum},
+		{"birthDate", ReasonMissing},
+		{"threshold", ReasonFormat},
+	}
+	if len(verr.Fields) != len(want) {
+		t.Fatalf("expected %d field errors, got %+v", len(want), verr.Fields)
+	}
+	for i, w := range want {
+		if verr.Fields[i].Field != w.field || verr.Fields[i].Reason != w.reason {
+			t.Errorf("field error %d = %+v, want %s %s", i, verr.Fields[i], w.field, w.reason)
+		}
+	}
+	if !errors.Is(err, ErrInvalidChecksum) || !errors.Is(err, ErrMissingField) {
+		t.Fatalf("expected the field errors to unwrap, got %v", err)
+	}
+	if len(f.calls) != 0 {
+		t.Fatalf("expected no http calls, got %v", f.calls)
+	}
+}
+
+func TestValidateLeavesInputsUnchanged(t *testing.T) {
+	f, c := newFakeServer(t, map[string]string{
+		EndpointMatchMobile: `{"error":false,"message":{"isMatched":true}}`,
+	})
+
+	s := c.NewMatchNationalCodeWithMobileNumberService().
+		NationalCode("۰۴۹۹۳۷۰۸۹۹").
+		MobileNumber("+98 912 345 6789")
+	err := s.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.nationalCode != "۰۴۹۹۳۷۰۸۹۹" || s.mobileNumber != "+98 912 345 6789" {
+		t.Fatalf("Validate rewrote the inputs: %q %q", s.nationalCode, s.mobileNumber)
+	}
+
+	_, err = s.Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := f.bodies[EndpointMatchMobile]
+	if body["nationalId"] != "0499370899" || body["mobileNumber"] != "09123456789" {
+		t.Fatalf("expected the parsed inputs to be sent, got %v", body)
+	}
+	if s.nationalCode != "۰۴۹۹۳۷۰۸۹۹" || s.mobileNumber != "+98 912 345 6789" {
+		t.Fatalf("Do rewrote the inputs: %q %q", s.nationalCode, s.mobileNumber)
+	}
+}